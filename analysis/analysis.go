@@ -0,0 +1,43 @@
+// Package analysis provides tools for the statistical analysis of cipher
+// components such as S-boxes and round functions. Results are collected in
+// matrix.Matrix values.
+//
+// The experiments estimate their results by sampling. They spread the work
+// over Workers goroutines, or runtime.GOMAXPROCS(0) if Workers is zero. All
+// randomness is derived from Seed with a separate stream for every key or
+// input bit, so the results only depend on the parameters and not on the
+// number of workers.
+package analysis
+
+import "errors"
+
+var (
+	ErrInvalidBits      = errors.New("Invalid number of bits")
+	ErrOutputOutOfRange = errors.New("Function output out of range")
+	ErrInvalidLength    = errors.New("Length is not a power of two")
+)
+
+// maxBits is the largest supported input or output size of the analysed
+// functions
+const maxBits = 16
+
+// checkBits panics with ErrInvalidBits if n is not a supported number of bits
+func checkBits(n int) {
+	if n < 1 || n > maxBits {
+		panic(ErrInvalidBits)
+	}
+}
+
+// tabulate evaluates f on every n-bit input. Panics with ErrOutputOutOfRange
+// if an output doesn't fit in outBits bits.
+func tabulate(f func(uint) uint, inBits, outBits int) []uint {
+	table := make([]uint, 1<<inBits)
+	for x := range table {
+		y := f(uint(x))
+		if y>>outBits != 0 {
+			panic(ErrOutputOutOfRange)
+		}
+		table[x] = y
+	}
+	return table
+}
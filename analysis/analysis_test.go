@@ -0,0 +1,47 @@
+package analysis_test
+
+// The S-box of the PRESENT block cipher
+var presentSBox = []uint{
+	0xc, 0x5, 0x6, 0xb, 0x9, 0x0, 0xa, 0xd,
+	0x3, 0xe, 0xf, 0x8, 0x4, 0x7, 0x1, 0x2,
+}
+
+// aesSBox computes the AES S-box: the multiplicative inverse in GF(2^8)
+// followed by the affine transformation
+func aesSBox() []uint {
+	mul := func(a, b uint) uint {
+		var p uint
+		for b > 0 {
+			if b&1 != 0 {
+				p ^= a
+			}
+			a <<= 1
+			if a&0x100 != 0 {
+				a ^= 0x11b
+			}
+			b >>= 1
+		}
+		return p
+	}
+	rotl8 := func(x uint, k int) uint {
+		return (x<<k | x>>(8-k)) & 0xff
+	}
+
+	sbox := make([]uint, 256)
+	for x := range uint(256) {
+		var inv uint
+		for y := range uint(256) {
+			if mul(x, y) == 1 {
+				inv = y
+			}
+		}
+		sbox[x] = inv ^ rotl8(inv, 1) ^ rotl8(inv, 2) ^ rotl8(inv, 3) ^ rotl8(inv, 4) ^ 0x63
+	}
+	return sbox
+}
+
+func lookup(sbox []uint) func(uint) uint {
+	return func(x uint) uint {
+		return sbox[x]
+	}
+}
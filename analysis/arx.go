@@ -0,0 +1,54 @@
+package analysis
+
+import "errors"
+
+var ErrInvalidRotation = errors.New("Invalid rotation amount")
+
+// ARXParameters describe the round function of a Speck-like ARX cipher that
+// maps the words (x, y) to (ROR(x, Alpha) + y, ROL(y, Beta) ^ (ROR(x, Alpha) + y))
+// before any key is mixed in.
+type ARXParameters struct {
+	WordSize int
+	Alpha    int
+	Beta     int
+}
+
+// The ARX parameters of the Speck variants
+var (
+	Speck32ARX  = ARXParameters{WordSize: 16, Alpha: 7, Beta: 2}
+	Speck48ARX  = ARXParameters{WordSize: 24, Alpha: 8, Beta: 3}
+	Speck64ARX  = ARXParameters{WordSize: 32, Alpha: 8, Beta: 3}
+	Speck96ARX  = ARXParameters{WordSize: 48, Alpha: 8, Beta: 3}
+	Speck128ARX = ARXParameters{WordSize: 64, Alpha: 8, Beta: 3}
+)
+
+// Validate returns ErrInvalidBits if the word size is not between 2 and 64
+// bits, or ErrInvalidRotation if a rotation amount is out of range.
+func (p ARXParameters) Validate() error {
+	if p.WordSize < 2 || p.WordSize > 64 {
+		return ErrInvalidBits
+	}
+	if p.Alpha < 0 || p.Alpha >= p.WordSize || p.Beta < 0 || p.Beta >= p.WordSize {
+		return ErrInvalidRotation
+	}
+	return nil
+}
+
+// check panics with the error returned by Validate, if any
+func (p ARXParameters) check() {
+	if err := p.Validate(); err != nil {
+		panic(err)
+	}
+}
+
+func (p ARXParameters) mask() uint64 {
+	return 1<<p.WordSize - 1
+}
+
+func (p ARXParameters) rotateLeft(x uint64, k int) uint64 {
+	return (x<<k | x>>(p.WordSize-k)) & p.mask()
+}
+
+func (p ARXParameters) rotateRight(x uint64, k int) uint64 {
+	return p.rotateLeft(x, (p.WordSize-k)%p.WordSize)
+}
@@ -0,0 +1,267 @@
+package analysis
+
+import (
+	"math"
+	"math/bits"
+	"math/rand/v2"
+
+	"git.omicron.one/playground/cryptography/cipher"
+	"git.omicron.one/playground/cryptography/matrix"
+)
+
+// Word is the type of the words of a round function
+type Word interface {
+	~uint16 | ~uint32 | ~uint64
+}
+
+// AvalancheExperiment measures how flipping a single bit of the plaintext or
+// the key changes the output of Function, which encrypts src with key into
+// dst. For every input bit Samples random plaintext and key pairs are
+// encrypted with and without the bit flipped.
+//
+// Bits are numbered from the most significant bit of the first byte on.
+type AvalancheExperiment struct {
+	Function   func(dst, key, src []byte) error
+	KeySize    int
+	InputSize  int
+	OutputSize int
+	Samples    int
+	Workers    int
+	Seed       uint64
+}
+
+// AvalancheReport holds the results of an AvalancheExperiment for the
+// plaintext bits and the key bits. Key is nil if the key size is zero.
+type AvalancheReport struct {
+	Plaintext *AvalancheResult `json:"plaintext"`
+	Key       *AvalancheResult `json:"key"`
+}
+
+// AvalancheResult holds the avalanche metrics for one kind of input bits.
+//
+// Flips is an input bits x output bits matrix with the probability that the
+// output bit changes when the input bit is flipped. The strict avalanche
+// criterion (SAC) requires all of them to be 1/2; SACDeviation is the largest
+// and MeanSACDeviation the mean absolute deviation from 1/2. Avalanche is the
+// mean fraction of output bits that change.
+//
+// BIC is an input bits x 1 matrix that holds, for each input bit, the largest
+// absolute correlation between the changes of two distinct output bits. The
+// bit independence criterion requires them to be zero; MaxBIC is the largest
+// of them. Output bits that always or never change have no defined
+// correlation and count as independent.
+type AvalancheResult struct {
+	Flips            *matrix.Matrix[float64] `json:"flips"`
+	BIC              *matrix.Matrix[float64] `json:"bic"`
+	Avalanche        float64                 `json:"avalanche"`
+	SACDeviation     float64                 `json:"sac_deviation"`
+	MeanSACDeviation float64                 `json:"mean_sac_deviation"`
+	MaxBIC           float64                 `json:"max_bic"`
+}
+
+// BlockFunction returns a function for AvalancheExperiment.Function that
+// creates a block cipher with New for every key and encrypts a single block.
+// The input and output size are the block size.
+func BlockFunction(New func(key []byte) (cipher.Block, error)) func(dst, key, src []byte) error {
+	return func(dst, key, src []byte) error {
+		b, err := New(key)
+		if err != nil {
+			return err
+		}
+		b.Encrypt(dst, src)
+		return nil
+	}
+}
+
+// RoundFunction returns a function for AvalancheExperiment.Function that
+// applies round, for example impl.Round128 of Speck or Simon, the given number
+// of times with independent round keys. The words have wordBits bits and are
+// stored big-endian in wordBits/8 bytes. The key consists of the round keys in
+// order, so the key size is rounds*wordBits/8 and the input and output size
+// are 2*wordBits/8.
+//
+// Panics with ErrInvalidBits if wordBits is not a multiple of 8 or larger
+// than the word type and with cipher.ErrInvalidRounds if rounds < 1.
+func RoundFunction[W Word](round func(k, x, y W) (W, W), wordBits, rounds int) func(dst, key, src []byte) error {
+	size := wordBits / 8
+	if wordBits < 8 || wordBits%8 != 0 || wordBits > bits.OnesCount64(uint64(^W(0))) {
+		panic(ErrInvalidBits)
+	}
+	if rounds < 1 {
+		panic(cipher.ErrInvalidRounds)
+	}
+
+	word := func(b []byte) W {
+		var w W
+		for _, v := range b[:size] {
+			w = w<<8 | W(v)
+		}
+		return w
+	}
+	putWord := func(b []byte, w W) {
+		for i := size - 1; i >= 0; i-- {
+			b[i] = byte(w)
+			w >>= 8
+		}
+	}
+	mask := W(uint64(1)<<(wordBits-1)<<1 - 1)
+
+	return func(dst, key, src []byte) error {
+		x, y := word(src), word(src[size:])
+		for r := range rounds {
+			x, y = round(word(key[r*size:]), x, y)
+			x, y = x&mask, y&mask
+		}
+		putWord(dst, x)
+		putWord(dst[size:], y)
+		return nil
+	}
+}
+
+// avalancheJob is the work for a single input bit. If key is true the bit is
+// a key bit, otherwise a plaintext bit.
+type avalancheJob struct {
+	key bool
+	bit int
+}
+
+// Run performs the experiment.
+// Returns ErrInvalidExperiment if any of the parameters is invalid or any
+// error returned by Function.
+func (e *AvalancheExperiment) Run() (*AvalancheReport, error) {
+	if e.Function == nil || e.KeySize < 0 || e.InputSize < 1 || e.OutputSize < 1 || e.Samples < 1 || e.Workers < 0 {
+		return nil, ErrInvalidExperiment
+	}
+
+	inBits, keyBits, outBits := 8*e.InputSize, 8*e.KeySize, 8*e.OutputSize
+	report := &AvalancheReport{
+		Plaintext: &AvalancheResult{
+			Flips: matrix.Create[float64](inBits, outBits),
+			BIC:   matrix.Create[float64](inBits, 1),
+		},
+	}
+	if keyBits > 0 {
+		report.Key = &AvalancheResult{
+			Flips: matrix.Create[float64](keyBits, outBits),
+			BIC:   matrix.Create[float64](keyBits, 1),
+		}
+	}
+
+	// The plaintext bits are followed by the key bits
+	err := runParallel(e.Workers, inBits+keyBits, func() func(int) error {
+		return func(bit int) error {
+			if bit < inBits {
+				return e.runBit(avalancheJob{bit: bit}, report.Plaintext)
+			}
+			return e.runBit(avalancheJob{key: true, bit: bit - inBits}, report.Key)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Plaintext.summarize()
+	if report.Key != nil {
+		report.Key.summarize()
+	}
+	return report, nil
+}
+
+// runBit performs the samples for a single input bit and stores the flip
+// probabilities and BIC of that bit in result
+func (e *AvalancheExperiment) runBit(job avalancheJob, result *AvalancheResult) error {
+	stream := uint64(job.bit)
+	if job.key {
+		stream += uint64(8 * e.InputSize)
+	}
+	rng := rand.New(rand.NewPCG(e.Seed, stream))
+
+	src := make([]byte, e.InputSize)
+	key := make([]byte, e.KeySize)
+	c1 := make([]byte, e.OutputSize)
+	c2 := make([]byte, e.OutputSize)
+
+	outBits := 8 * e.OutputSize
+	flips := make([]int, outBits)
+	pairs := make([]int, outBits*outBits)
+	diff := make([]uint64, (outBits+63)/64)
+	changed := make([]int, 0, outBits)
+
+	flipped := src
+	if job.key {
+		flipped = key
+	}
+	for range e.Samples {
+		fillRandom(rng, src)
+		fillRandom(rng, key)
+		if err := e.Function(c1, key, src); err != nil {
+			return err
+		}
+		flipped[job.bit/8] ^= 0x80 >> (job.bit % 8)
+		if err := e.Function(c2, key, src); err != nil {
+			return err
+		}
+
+		clear(diff)
+		for i := range c1 {
+			diff[i/8] |= uint64(c1[i]^c2[i]) << (56 - 8*(i%8))
+		}
+
+		// Count every changed output bit and every pair of them
+		changed = changedBits(changed[:0], diff)
+		for a, j := range changed {
+			flips[j]++
+			row := pairs[j*outBits:]
+			for _, k := range changed[a+1:] {
+				row[k]++
+			}
+		}
+	}
+
+	n := float64(e.Samples)
+	bic := 0.0
+	for j := range outBits {
+		pj := float64(flips[j]) / n
+		result.Flips.Set(job.bit, j, pj)
+		for k := j + 1; k < outBits; k++ {
+			pk := float64(flips[k]) / n
+			variance := pj * (1 - pj) * pk * (1 - pk)
+			if variance == 0 {
+				continue
+			}
+			c := (float64(pairs[j*outBits+k])/n - pj*pk) / math.Sqrt(variance)
+			bic = max(bic, math.Abs(c))
+		}
+	}
+	result.BIC.Set(job.bit, 0, bic)
+	return nil
+}
+
+// changedBits appends the indices of the set bits in diff to dst in
+// increasing order, where bit 0 is the most significant bit of diff[0]
+func changedBits(dst []int, diff []uint64) []int {
+	for i, d := range diff {
+		for d != 0 {
+			lz := bits.LeadingZeros64(d)
+			dst = append(dst, 64*i+lz)
+			d &^= 1 << (63 - lz)
+		}
+	}
+	return dst
+}
+
+// summarize computes the summary metrics from Flips and BIC
+func (r *AvalancheResult) summarize() {
+	rows, cols := r.Flips.Size()
+	for i := range rows {
+		for j := range cols {
+			p := r.Flips.Get(i, j)
+			r.Avalanche += p
+			r.SACDeviation = max(r.SACDeviation, math.Abs(p-0.5))
+			r.MeanSACDeviation += math.Abs(p - 0.5)
+		}
+		r.MaxBIC = max(r.MaxBIC, r.BIC.Get(i, 0))
+	}
+	r.Avalanche /= float64(rows * cols)
+	r.MeanSACDeviation /= float64(rows * cols)
+}
@@ -0,0 +1,160 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"git.omicron.one/playground/cryptography/analysis"
+	"git.omicron.one/playground/cryptography/cipher"
+	"git.omicron.one/playground/cryptography/cipher/speck"
+	"git.omicron.one/playground/cryptography/cipher/speck/impl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAvalancheRound(t *testing.T) {
+	// In a single Speck32 round the key is only added after the modular
+	// addition, so flipping its least significant bit flips exactly the least
+	// significant bits of both words
+	e := &analysis.AvalancheExperiment{
+		Function:   analysis.RoundFunction(impl.Round32, 16, 1),
+		KeySize:    2,
+		InputSize:  4,
+		OutputSize: 4,
+		Samples:    256,
+		Seed:       1,
+	}
+	report, err := e.Run()
+	assert.Nil(t, err)
+
+	assert.Equal(t, 32, report.Plaintext.Flips.Rows())
+	assert.Equal(t, 32, report.Plaintext.Flips.Cols())
+	assert.Equal(t, 16, report.Key.Flips.Rows())
+	for j := range 32 {
+		expected := 0.0
+		if j == 15 || j == 31 {
+			expected = 1
+		}
+		assert.Equal(t, expected, report.Key.Flips.Get(15, j), "bit %d", j)
+	}
+	assert.Zero(t, report.Key.BIC.Get(15, 0))
+
+	// The least significant bit of the second word always changes the least
+	// significant bit of the sum
+	assert.Equal(t, 1.0, report.Plaintext.Flips.Get(31, 15))
+
+	// A single round is far from the strict avalanche criterion
+	assert.Equal(t, 0.5, report.Plaintext.SACDeviation)
+	assert.Equal(t, 0.5, report.Key.SACDeviation)
+	assert.Less(t, report.Key.Avalanche, 0.25)
+}
+
+func TestAvalancheBlock(t *testing.T) {
+	e := &analysis.AvalancheExperiment{
+		Function: analysis.BlockFunction(func(key []byte) (cipher.Block, error) {
+			return speck.New(key, speck.Speck64128)
+		}),
+		KeySize:    16,
+		InputSize:  8,
+		OutputSize: 8,
+		Samples:    2000,
+		Seed:       2,
+	}
+	report, err := e.Run()
+	assert.Nil(t, err)
+
+	for _, result := range []*analysis.AvalancheResult{report.Plaintext, report.Key} {
+		assert.InDelta(t, 0.5, result.Avalanche, 0.005)
+		assert.Less(t, result.SACDeviation, 0.08)
+		assert.Less(t, result.MeanSACDeviation, 0.02)
+		assert.Greater(t, result.MaxBIC, 0.0)
+		assert.Less(t, result.MaxBIC, 0.2)
+	}
+}
+
+func TestAvalancheReduced(t *testing.T) {
+	e := &analysis.AvalancheExperiment{
+		Function:   analysis.BlockFunction(analysis.ReducedSpeck128(2)),
+		KeySize:    impl.KeySize128128,
+		InputSize:  16,
+		OutputSize: 16,
+		Samples:    64,
+		Workers:    3,
+		Seed:       3,
+	}
+	report, err := e.Run()
+	assert.Nil(t, err)
+	assert.Equal(t, 0.5, report.Plaintext.SACDeviation)
+	assert.Greater(t, report.Plaintext.MaxBIC, 0.9)
+
+	// The result doesn't depend on the number of workers
+	for _, workers := range []int{0, 1, 32} {
+		e.Workers = workers
+		other, err := e.Run()
+		assert.Nil(t, err)
+		assert.Equal(t, report, other)
+	}
+
+	// But it does depend on the seed
+	e.Seed++
+	other, err := e.Run()
+	assert.Nil(t, err)
+	assert.NotEqual(t, report.Key.Flips, other.Key.Flips)
+}
+
+func TestAvalancheKeyless(t *testing.T) {
+	round := func(k, x, y uint64) (uint64, uint64) {
+		return impl.Round128(0, x, y)
+	}
+	e := &analysis.AvalancheExperiment{
+		Function: func(dst, key, src []byte) error {
+			return analysis.RoundFunction(round, 64, 4)(dst, make([]byte, 32), src)
+		},
+		InputSize:  16,
+		OutputSize: 16,
+		Samples:    16,
+	}
+	report, err := e.Run()
+	assert.Nil(t, err)
+	assert.NotNil(t, report.Plaintext)
+	assert.Nil(t, report.Key)
+}
+
+func TestAvalancheInvalid(t *testing.T) {
+	valid := func() *analysis.AvalancheExperiment {
+		return &analysis.AvalancheExperiment{
+			Function:   analysis.BlockFunction(analysis.ReducedSpeck128(1)),
+			KeySize:    impl.KeySize128128,
+			InputSize:  16,
+			OutputSize: 16,
+			Samples:    1,
+		}
+	}
+
+	for _, modify := range []func(e *analysis.AvalancheExperiment){
+		func(e *analysis.AvalancheExperiment) { e.Function = nil },
+		func(e *analysis.AvalancheExperiment) { e.KeySize = -1 },
+		func(e *analysis.AvalancheExperiment) { e.InputSize = 0 },
+		func(e *analysis.AvalancheExperiment) { e.OutputSize = 0 },
+		func(e *analysis.AvalancheExperiment) { e.Samples = 0 },
+		func(e *analysis.AvalancheExperiment) { e.Workers = -1 },
+	} {
+		e := valid()
+		modify(e)
+		_, err := e.Run()
+		assert.ErrorIs(t, err, analysis.ErrInvalidExperiment)
+	}
+
+	e := valid()
+	e.Function = analysis.BlockFunction(analysis.ReducedSpeck128(0))
+	_, err := e.Run()
+	assert.ErrorIs(t, err, cipher.ErrInvalidRounds)
+
+	assert.PanicsWithValue(t, analysis.ErrInvalidBits, func() {
+		analysis.RoundFunction(impl.Round32, 24, 1)
+	})
+	assert.PanicsWithValue(t, analysis.ErrInvalidBits, func() {
+		analysis.RoundFunction(impl.Round48, 20, 1)
+	})
+	assert.PanicsWithValue(t, cipher.ErrInvalidRounds, func() {
+		analysis.RoundFunction(impl.Round128, 64, 0)
+	})
+}
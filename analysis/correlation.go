@@ -0,0 +1,109 @@
+package analysis
+
+import (
+	"math"
+	"math/bits"
+	"math/rand/v2"
+
+	"git.omicron.one/playground/cryptography/cipher/speck/impl"
+	"git.omicron.one/playground/cryptography/matrix"
+)
+
+// CorrelationExperiment estimates the correlation of the linear approximation
+// InputMask -> OutputMask over Rounds rounds of Speck128 empirically. The
+// instances are built from impl.Round128 with the round keys of the Speck128
+// key schedule; the X and Y fields of the masks apply to the first and second
+// argument of impl.Round128. For each of Keys random 128-bit keys Samples
+// random plaintexts are encrypted.
+type CorrelationExperiment struct {
+	Rounds     int
+	InputMask  Mask
+	OutputMask Mask
+	Keys       int
+	Samples    int
+	Workers    int
+	Seed       uint64
+}
+
+// CorrelationResult holds the outcome of a CorrelationExperiment.
+// KeyCorrelations is a Keys x 1 matrix with the correlation measured for each
+// key. As the sign of a correlation depends on the key, Correlation, the mean
+// over all keys, tends to zero while Potential, the mean of the squared
+// correlations, estimates the expected linear potential. StandardError is the
+// standard error of a single key's correlation.
+type CorrelationResult struct {
+	Rounds          int                     `json:"rounds"`
+	InputMask       Mask                    `json:"input_mask"`
+	OutputMask      Mask                    `json:"output_mask"`
+	Keys            int                     `json:"keys"`
+	Samples         int                     `json:"samples"`
+	Seed            uint64                  `json:"seed"`
+	KeyCorrelations *matrix.Matrix[float64] `json:"key_correlations"`
+	Correlation     float64                 `json:"correlation"`
+	Potential       float64                 `json:"potential"`
+	StandardError   float64                 `json:"standard_error"`
+}
+
+// Run performs the experiment.
+// Returns ErrInvalidExperiment if any of the parameters is invalid.
+func (e *CorrelationExperiment) Run() (*CorrelationResult, error) {
+	if e.Rounds < 1 || e.Keys < 1 || e.Samples < 1 || e.Workers < 0 {
+		return nil, ErrInvalidExperiment
+	}
+
+	correlations := matrix.Create[float64](e.Keys, 1)
+	runParallel(e.Workers, e.Keys, func() func(int) error {
+		return func(k int) error {
+			correlations.Set(k, 0, e.runKey(k))
+			return nil
+		}
+	})
+
+	result := &CorrelationResult{
+		Rounds:          e.Rounds,
+		InputMask:       e.InputMask,
+		OutputMask:      e.OutputMask,
+		Keys:            e.Keys,
+		Samples:         e.Samples,
+		Seed:            e.Seed,
+		KeyCorrelations: correlations,
+		StandardError:   1 / math.Sqrt(float64(e.Samples)),
+	}
+	for k := range e.Keys {
+		c := correlations.Get(k, 0)
+		result.Correlation += c
+		result.Potential += c * c
+	}
+	result.Correlation /= float64(e.Keys)
+	result.Potential /= float64(e.Keys)
+	return result, nil
+}
+
+// runKey measures the correlation for the key with index k
+func (e *CorrelationExperiment) runKey(k int) float64 {
+	rng := rand.New(rand.NewPCG(e.Seed, uint64(k)))
+
+	key := make([]byte, impl.KeySize128128)
+	fillRandom(rng, key)
+	// Rounds has been validated, so this can't fail
+	ctx, _ := impl.New128Rounds(key, e.Rounds)
+
+	agree := 0
+	for range e.Samples {
+		x, y := rng.Uint64(), rng.Uint64()
+		in := x&e.InputMask.X ^ y&e.InputMask.Y
+		for _, rk := range ctx.Keys {
+			x, y = impl.Round128(rk, x, y)
+		}
+		out := x&e.OutputMask.X ^ y&e.OutputMask.Y
+		agree += 1 - bits.OnesCount64(in^out)&1
+	}
+	return float64(2*agree-e.Samples) / float64(e.Samples)
+}
+
+// Log2Correlation returns the base 2 logarithm of the root mean square
+// correlation, comparable to LinearTrail.Log2Correlation. Returns -Inf if all
+// measured correlations are zero.
+func (r *CorrelationResult) Log2Correlation() float64 {
+	return math.Log2(r.Potential) / 2
+}
@@ -0,0 +1,102 @@
+package analysis_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/analysis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorrelationExperimentCertain(t *testing.T) {
+	// The least significant bit of the sum is linear, so for one round the
+	// approximation holds with a correlation of plus or minus one,
+	// depending on the key
+	e := &analysis.CorrelationExperiment{
+		Rounds:     1,
+		InputMask:  analysis.Mask{X: 1 << 8, Y: 1},
+		OutputMask: analysis.Mask{X: 1},
+		Keys:       4,
+		Samples:    100,
+		Seed:       1,
+	}
+	result, err := e.Run()
+	assert.Nil(t, err)
+	assert.Equal(t, 4, result.KeyCorrelations.Rows())
+	for k := range 4 {
+		c := result.KeyCorrelations.Get(k, 0)
+		assert.Equal(t, 1.0, c*c)
+	}
+	assert.Equal(t, 1.0, result.Potential)
+	assert.Equal(t, 0.0, result.Log2Correlation())
+	assert.Equal(t, 0.1, result.StandardError)
+}
+
+func TestCorrelationExperimentTrail(t *testing.T) {
+	trail := analysis.SearchLinearTrails(analysis.Speck128ARX, 3)[2]
+	e := &analysis.CorrelationExperiment{
+		Rounds:     trail.Rounds(),
+		InputMask:  trail.Masks[0],
+		OutputMask: trail.Masks[3],
+		Keys:       16,
+		Samples:    1 << 12,
+		Workers:    4,
+		Seed:       42,
+	}
+	result, err := e.Run()
+	assert.Nil(t, err)
+
+	// A single dominant trail determines the correlation of the approximation
+	assert.InDelta(t, trail.Log2Correlation(), result.Log2Correlation(), 0.2)
+
+	// The result doesn't depend on the number of workers
+	for _, workers := range []int{0, 1, 3, 32} {
+		e.Workers = workers
+		other, err := e.Run()
+		assert.Nil(t, err)
+		assert.Equal(t, result, other)
+	}
+
+	// But it does depend on the seed
+	e.Seed++
+	other, err := e.Run()
+	assert.Nil(t, err)
+	assert.NotEqual(t, result.KeyCorrelations, other.KeyCorrelations)
+}
+
+func TestCorrelationResultJSON(t *testing.T) {
+	e := &analysis.CorrelationExperiment{
+		Rounds:     2,
+		InputMask:  analysis.Mask{X: 1, Y: 2},
+		OutputMask: analysis.Mask{X: 3, Y: 4},
+		Keys:       2,
+		Samples:    16,
+		Seed:       7,
+	}
+	result, err := e.Run()
+	assert.Nil(t, err)
+
+	data, err := json.Marshal(result)
+	assert.Nil(t, err)
+
+	var decoded map[string]any
+	assert.Nil(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, 2.0, decoded["rounds"])
+	assert.Equal(t, map[string]any{"x": 1.0, "y": 2.0}, decoded["input_mask"])
+	assert.Equal(t, map[string]any{"x": 3.0, "y": 4.0}, decoded["output_mask"])
+	assert.Equal(t, 16.0, decoded["samples"])
+	assert.Len(t, decoded["key_correlations"], 2)
+	assert.Contains(t, decoded, "potential")
+}
+
+func TestCorrelationExperimentInvalid(t *testing.T) {
+	for _, e := range []analysis.CorrelationExperiment{
+		{Rounds: 0, Keys: 1, Samples: 1},
+		{Rounds: 1, Keys: 0, Samples: 1},
+		{Rounds: 1, Keys: 1, Samples: 0},
+		{Rounds: 1, Keys: 1, Samples: 1, Workers: -1},
+	} {
+		_, err := e.Run()
+		assert.ErrorIs(t, err, analysis.ErrInvalidExperiment)
+	}
+}
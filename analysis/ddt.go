@@ -0,0 +1,73 @@
+package analysis
+
+import (
+	"git.omicron.one/playground/cryptography/matrix"
+)
+
+// DDT is the difference distribution table of a function from inBits to
+// outBits bits. Entry (a, b) of Table counts the inputs x for which
+// f(x) ^ f(x ^ a) == b.
+type DDT struct {
+	Table   *matrix.Matrix[int]
+	InBits  int
+	OutBits int
+}
+
+// NewDDT computes the difference distribution table of f, which is called for
+// every inBits-bit input and must return outBits-bit values. This can be used
+// for S-boxes as well as for small bijections.
+//
+// Panics with ErrInvalidBits if inBits or outBits is not between 1 and 16.
+// Panics with ErrOutputOutOfRange if f returns a value with more than outBits
+// bits.
+func NewDDT(f func(uint) uint, inBits, outBits int) *DDT {
+	checkBits(inBits)
+	checkBits(outBits)
+
+	table := tabulate(f, inBits, outBits)
+	m := matrix.Create[int](1<<inBits, 1<<outBits)
+
+	for a := range table {
+		for x := range table {
+			b := int(table[x] ^ table[x^a])
+			m.Set(a, b, m.Get(a, b)+1)
+		}
+	}
+
+	return &DDT{
+		Table:   m,
+		InBits:  inBits,
+		OutBits: outBits,
+	}
+}
+
+// Uniformity returns the differential uniformity: the largest entry of the
+// table for a non-zero input difference.
+func (d *DDT) Uniformity() int {
+	best := 0
+	for a := 1; a < d.Table.Rows(); a++ {
+		for b := range d.Table.Cols() {
+			best = max(best, d.Table.Get(a, b))
+		}
+	}
+	return best
+}
+
+// MaxProbability returns the highest probability of any differential with a
+// non-zero input difference, which is the differential uniformity divided by
+// the number of inputs.
+func (d *DDT) MaxProbability() float64 {
+	return float64(d.Uniformity()) / float64(d.Table.Rows())
+}
+
+// EntryCounts returns how often each value occurs in the table, ignoring the
+// trivial row of the zero input difference.
+func (d *DDT) EntryCounts() map[int]int {
+	counts := make(map[int]int)
+	for a := 1; a < d.Table.Rows(); a++ {
+		for b := range d.Table.Cols() {
+			counts[d.Table.Get(a, b)]++
+		}
+	}
+	return counts
+}
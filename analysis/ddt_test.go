@@ -0,0 +1,109 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"git.omicron.one/playground/cryptography/analysis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDDTPresent(t *testing.T) {
+	ddt := analysis.NewDDT(lookup(presentSBox), 4, 4)
+	assert.Equal(t, 4, ddt.InBits)
+	assert.Equal(t, 4, ddt.OutBits)
+	assert.Equal(t, 16, ddt.Table.Rows())
+	assert.Equal(t, 16, ddt.Table.Cols())
+
+	// The zero difference always maps to itself
+	assert.Equal(t, 16, ddt.Table.Get(0, 0))
+	for a := range 16 {
+		sum := 0
+		for b := range 16 {
+			sum += ddt.Table.Get(a, b)
+			assert.Zero(t, ddt.Table.Get(a, b)%2)
+		}
+		assert.Equal(t, 16, sum)
+	}
+
+	// PRESENT never maps a single bit input difference to a single bit output
+	// difference
+	for _, a := range []int{1, 2, 4, 8} {
+		for _, b := range []int{1, 2, 4, 8} {
+			assert.Zero(t, ddt.Table.Get(a, b))
+		}
+	}
+
+	assert.Equal(t, 4, ddt.Uniformity())
+	assert.Equal(t, 0.25, ddt.MaxProbability())
+
+	counts := ddt.EntryCounts()
+	total := 0
+	for value, count := range counts {
+		total += count
+		assert.Contains(t, []int{0, 2, 4}, value)
+	}
+	assert.Equal(t, 15*16, total)
+	assert.Equal(t, 15*16, counts[2]*2+counts[4]*4)
+}
+
+func TestDDTAES(t *testing.T) {
+	ddt := analysis.NewDDT(lookup(aesSBox()), 8, 8)
+
+	assert.Equal(t, 4, ddt.Uniformity())
+	assert.Equal(t, 4.0/256, ddt.MaxProbability())
+
+	// Every row of the AES DDT has a single 4, 126 twos and 129 zeros
+	assert.Equal(t, map[int]int{
+		0: 255 * 129,
+		2: 255 * 126,
+		4: 255,
+	}, ddt.EntryCounts())
+}
+
+func TestDDTLinear(t *testing.T) {
+	// A linear function maps every difference deterministically
+	f := func(x uint) uint {
+		return (x<<1 | x>>4) & 0x1f
+	}
+	ddt := analysis.NewDDT(f, 5, 5)
+
+	assert.Equal(t, 32, ddt.Uniformity())
+	assert.Equal(t, 1.0, ddt.MaxProbability())
+	for a := range 32 {
+		assert.Equal(t, 32, ddt.Table.Get(a, int(f(uint(a)))))
+	}
+	assert.Equal(t, map[int]int{0: 31 * 31, 32: 31}, ddt.EntryCounts())
+}
+
+func TestDDTDifferentSizes(t *testing.T) {
+	// Truncating a bijection to its two lowest bits
+	ddt := analysis.NewDDT(func(x uint) uint {
+		return presentSBox[x] & 3
+	}, 4, 2)
+	assert.Equal(t, 16, ddt.Table.Rows())
+	assert.Equal(t, 4, ddt.Table.Cols())
+
+	for a := range 16 {
+		sum := 0
+		for b := range 4 {
+			sum += ddt.Table.Get(a, b)
+		}
+		assert.Equal(t, 16, sum)
+	}
+}
+
+func TestDDTInvalid(t *testing.T) {
+	identity := func(x uint) uint {
+		return x
+	}
+
+	assert.PanicsWithValue(t, analysis.ErrInvalidBits, func() {
+		analysis.NewDDT(identity, 0, 4)
+	})
+	assert.PanicsWithValue(t, analysis.ErrInvalidBits, func() {
+		analysis.NewDDT(identity, 4, 17)
+	})
+	assert.PanicsWithValue(t, analysis.ErrOutputOutOfRange, func() {
+		analysis.NewDDT(identity, 4, 3)
+	})
+}
@@ -0,0 +1,183 @@
+package analysis
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"math/rand/v2"
+
+	"git.omicron.one/playground/cryptography/cipher"
+	"git.omicron.one/playground/cryptography/cipher/speck/impl"
+	"git.omicron.one/playground/cryptography/matrix"
+)
+
+var (
+	ErrInvalidExperiment = errors.New("Invalid experiment parameters")
+	ErrInvalidDifference = errors.New("Difference doesn't match the block size")
+)
+
+// DifferentialExperiment estimates the probability of a differential
+// (InputDifference -> OutputDifference) empirically. For each of Keys random
+// keys a cipher is created with New and Samples random plaintext pairs with
+// the input difference are encrypted.
+type DifferentialExperiment struct {
+	New              func(key []byte) (cipher.Block, error)
+	KeySize          int
+	InputDifference  []byte
+	OutputDifference []byte
+	Keys             int
+	Samples          int
+	Workers          int
+	Seed             uint64
+}
+
+// DifferentialResult holds the outcome of a DifferentialExperiment.
+// KeyHits is a Keys x 1 matrix with the number of pairs that followed the
+// differential for each key. OutputBits is a 1 x (8 * block size) histogram
+// that counts how often every bit of the observed output difference was set,
+// with bit 0 being the most significant bit of the first byte.
+type DifferentialResult struct {
+	Hits       int
+	Trials     int
+	KeyHits    *matrix.Matrix[int]
+	OutputBits *matrix.Matrix[int]
+}
+
+// ReducedSpeck128 returns a constructor for Speck128 instances reduced to the
+// given number of rounds, for use in DifferentialExperiment.New
+func ReducedSpeck128(rounds int) func(key []byte) (cipher.Block, error) {
+	return func(key []byte) (cipher.Block, error) {
+		ctx, err := impl.New128Rounds(key, rounds)
+		if err != nil {
+			return nil, err
+		}
+		return ctx, nil
+	}
+}
+
+// Run performs the experiment.
+// Returns ErrInvalidExperiment if any of the parameters is invalid,
+// ErrInvalidDifference if the differences don't match the block size or any
+// error returned by New.
+func (e *DifferentialExperiment) Run() (*DifferentialResult, error) {
+	if e.New == nil || e.KeySize < 0 || e.Keys < 1 || e.Samples < 1 || e.Workers < 0 {
+		return nil, ErrInvalidExperiment
+	}
+	if len(e.InputDifference) == 0 || len(e.InputDifference) != len(e.OutputDifference) {
+		return nil, ErrInvalidDifference
+	}
+
+	keyHits := matrix.Create[int](e.Keys, 1)
+	outputBits := matrix.NewAccumulator[int](1, 8*len(e.OutputDifference))
+	err := runParallel(e.Workers, e.Keys, func() func(int) error {
+		histogram := outputBits.Shard()
+		return func(k int) error {
+			hits, err := e.runKey(k, histogram)
+			keyHits.Set(k, 0, hits)
+			return err
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DifferentialResult{
+		Trials:     e.Keys * e.Samples,
+		KeyHits:    keyHits,
+		OutputBits: outputBits.Merge(),
+	}
+	for k := range e.Keys {
+		result.Hits += keyHits.Get(k, 0)
+	}
+	return result, nil
+}
+
+// runKey performs the samples for the key with index k and adds the output
+// difference bits to the histogram. Returns the number of hits.
+func (e *DifferentialExperiment) runKey(k int, histogram *matrix.Matrix[int]) (int, error) {
+	rng := rand.New(rand.NewPCG(e.Seed, uint64(k)))
+
+	key := make([]byte, e.KeySize)
+	fillRandom(rng, key)
+	b, err := e.New(key)
+	if err != nil {
+		return 0, err
+	}
+	bs := b.BlockSize()
+	if bs != len(e.InputDifference) {
+		return 0, ErrInvalidDifference
+	}
+
+	p1 := make([]byte, bs)
+	p2 := make([]byte, bs)
+	c1 := make([]byte, bs)
+	c2 := make([]byte, bs)
+
+	hits := 0
+	for range e.Samples {
+		fillRandom(rng, p1)
+		for i := range p2 {
+			p2[i] = p1[i] ^ e.InputDifference[i]
+		}
+		b.Encrypt(c1, p1)
+		b.Encrypt(c2, p2)
+
+		match := true
+		for i := range c1 {
+			d := c1[i] ^ c2[i]
+			match = match && d == e.OutputDifference[i]
+			for ; d != 0; d &= d - 1 {
+				bit := 8*i + bits.LeadingZeros8(d)
+				histogram.Set(0, bit, histogram.Get(0, bit)+1)
+			}
+		}
+		if match {
+			hits++
+		}
+	}
+	return hits, nil
+}
+
+// fillRandom fills b with bytes from rng
+func fillRandom(rng *rand.Rand, b []byte) {
+	for i := 0; i < len(b); i += 8 {
+		v := rng.Uint64()
+		for j := i; j < min(i+8, len(b)); j++ {
+			b[j] = byte(v)
+			v >>= 8
+		}
+	}
+}
+
+// Probability returns the estimated probability of the differential
+func (r *DifferentialResult) Probability() float64 {
+	return float64(r.Hits) / float64(r.Trials)
+}
+
+// Log2Probability returns the base 2 logarithm of the estimated probability.
+// Returns -Inf if no pair followed the differential.
+func (r *DifferentialResult) Log2Probability() float64 {
+	return math.Log2(r.Probability())
+}
+
+// ConfidenceInterval returns the Wilson score interval of the probability for
+// the given confidence level, for example 0.95. Unlike the normal
+// approximation it remains meaningful for the very small probabilities and
+// hit counts that are common in differential experiments. Two runs can be
+// considered consistent if their intervals overlap.
+//
+// Panics if the confidence level is not strictly between 0 and 1.
+func (r *DifferentialResult) ConfidenceInterval(confidence float64) (float64, float64) {
+	if confidence <= 0 || confidence >= 1 {
+		panic("Invalid confidence level")
+	}
+
+	z := math.Sqrt2 * math.Erfinv(confidence)
+	n := float64(r.Trials)
+	p := r.Probability()
+
+	denominator := 1 + z*z/n
+	center := (p + z*z/(2*n)) / denominator
+	spread := z / denominator * math.Sqrt(p*(1-p)/n+z*z/(4*n*n))
+	return max(0, center-spread), min(1, center+spread)
+}
@@ -0,0 +1,163 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"git.omicron.one/playground/cryptography/analysis"
+	"git.omicron.one/playground/cryptography/cipher"
+	"git.omicron.one/playground/cryptography/cipher/speck/impl"
+	. "git.omicron.one/playground/cryptography/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDifferentialExperimentCertain(t *testing.T) {
+	// With a single round this differential holds with probability one
+	e := &analysis.DifferentialExperiment{
+		New:              analysis.ReducedSpeck128(1),
+		KeySize:          impl.KeySize128128,
+		InputDifference:  DeHex("00000000000000800000000000000000"),
+		OutputDifference: DeHex("80000000000000008000000000000000"),
+		Keys:             8,
+		Samples:          100,
+		Seed:             1,
+	}
+	result, err := e.Run()
+	assert.Nil(t, err)
+	assert.Equal(t, 800, result.Trials)
+	assert.Equal(t, 800, result.Hits)
+	assert.Equal(t, 1.0, result.Probability())
+	assert.Equal(t, 0.0, result.Log2Probability())
+
+	assert.Equal(t, 8, result.KeyHits.Rows())
+	for k := range 8 {
+		assert.Equal(t, 100, result.KeyHits.Get(k, 0))
+	}
+
+	assert.Equal(t, 128, result.OutputBits.Cols())
+	for bit := range 128 {
+		if bit == 0 || bit == 64 {
+			assert.Equal(t, 800, result.OutputBits.Get(0, bit))
+		} else {
+			assert.Zero(t, result.OutputBits.Get(0, bit))
+		}
+	}
+
+	lo, hi := result.ConfidenceInterval(0.95)
+	assert.Less(t, lo, 1.0)
+	assert.Greater(t, lo, 0.99)
+	assert.Equal(t, 1.0, hi)
+}
+
+func TestDifferentialExperimentTrail(t *testing.T) {
+	trail := analysis.SearchDifferentialTrails(analysis.Speck128ARX, 3)[2]
+	in, out := trail.Differences[0], trail.Differences[3]
+
+	block := func(d analysis.Difference) []byte {
+		b := make([]byte, 16)
+		for i := range 8 {
+			b[i] = byte(d.X >> (56 - 8*i))
+			b[8+i] = byte(d.Y >> (56 - 8*i))
+		}
+		return b
+	}
+
+	e := &analysis.DifferentialExperiment{
+		New:              analysis.ReducedSpeck128(trail.Rounds()),
+		KeySize:          impl.KeySize128256,
+		InputDifference:  block(in),
+		OutputDifference: block(out),
+		Keys:             16,
+		Samples:          1 << 10,
+		Workers:          4,
+		Seed:             42,
+	}
+	result, err := e.Run()
+	assert.Nil(t, err)
+
+	// The trail probability is a lower bound for the differential
+	_, hi := result.ConfidenceInterval(0.999)
+	assert.GreaterOrEqual(t, hi, 1.0/8)
+
+	// The result doesn't depend on the number of workers
+	for _, workers := range []int{0, 1, 3, 32} {
+		e.Workers = workers
+		other, err := e.Run()
+		assert.Nil(t, err)
+		assert.Equal(t, result, other)
+	}
+
+	// But it does depend on the seed
+	e.Seed++
+	other, err := e.Run()
+	assert.Nil(t, err)
+	assert.NotEqual(t, result.OutputBits, other.OutputBits)
+}
+
+func TestDifferentialExperimentConfidenceInterval(t *testing.T) {
+	result := &analysis.DifferentialResult{Hits: 0, Trials: 1000}
+	assert.Equal(t, 0.0, result.Probability())
+	lo, hi := result.ConfidenceInterval(0.95)
+	assert.Equal(t, 0.0, lo)
+	assert.InDelta(t, 0.00383, hi, 1e-5)
+
+	result = &analysis.DifferentialResult{Hits: 50, Trials: 100}
+	lo, hi = result.ConfidenceInterval(0.95)
+	assert.InDelta(t, 0.4038, lo, 1e-4)
+	assert.InDelta(t, 0.5962, hi, 1e-4)
+
+	assert.PanicsWithValue(t, "Invalid confidence level", func() {
+		result.ConfidenceInterval(1)
+	})
+	assert.PanicsWithValue(t, "Invalid confidence level", func() {
+		result.ConfidenceInterval(0)
+	})
+}
+
+func TestDifferentialExperimentInvalid(t *testing.T) {
+	valid := func() *analysis.DifferentialExperiment {
+		return &analysis.DifferentialExperiment{
+			New:              analysis.ReducedSpeck128(1),
+			KeySize:          impl.KeySize128128,
+			InputDifference:  make([]byte, 16),
+			OutputDifference: make([]byte, 16),
+			Keys:             1,
+			Samples:          1,
+		}
+	}
+
+	e := valid()
+	e.Keys = 0
+	_, err := e.Run()
+	assert.ErrorIs(t, err, analysis.ErrInvalidExperiment)
+
+	e = valid()
+	e.Samples = 0
+	_, err = e.Run()
+	assert.ErrorIs(t, err, analysis.ErrInvalidExperiment)
+
+	e = valid()
+	e.New = nil
+	_, err = e.Run()
+	assert.ErrorIs(t, err, analysis.ErrInvalidExperiment)
+
+	e = valid()
+	e.OutputDifference = make([]byte, 8)
+	_, err = e.Run()
+	assert.ErrorIs(t, err, analysis.ErrInvalidDifference)
+
+	e = valid()
+	e.InputDifference = make([]byte, 8)
+	e.OutputDifference = make([]byte, 8)
+	_, err = e.Run()
+	assert.ErrorIs(t, err, analysis.ErrInvalidDifference)
+
+	e = valid()
+	e.KeySize = 5
+	_, err = e.Run()
+	assert.ErrorIs(t, err, cipher.ErrInvalidKeyLength)
+
+	e = valid()
+	e.New = analysis.ReducedSpeck128(0)
+	_, err = e.Run()
+	assert.ErrorIs(t, err, cipher.ErrInvalidRounds)
+}
@@ -0,0 +1,76 @@
+package analysis
+
+import (
+	"git.omicron.one/playground/cryptography/matrix"
+)
+
+// LAT is the linear approximation table of a function from inBits to outBits
+// bits. Entry (a, b) of Table is the number of inputs x for which
+// a·x == b·f(x) minus half the number of inputs, where a·x is the parity of
+// a & x. Dividing an entry by the number of inputs gives the bias of the
+// approximation.
+type LAT struct {
+	Table   *matrix.Matrix[int]
+	InBits  int
+	OutBits int
+}
+
+// NewLAT computes the linear approximation table of f, which is called for
+// every inBits-bit input and must return outBits-bit values. Every column is
+// computed with a fast Walsh-Hadamard transform.
+//
+// Panics with ErrInvalidBits if inBits or outBits is not between 1 and 16.
+// Panics with ErrOutputOutOfRange if f returns a value with more than outBits
+// bits.
+func NewLAT(f func(uint) uint, inBits, outBits int) *LAT {
+	checkBits(inBits)
+	checkBits(outBits)
+
+	table := tabulate(f, inBits, outBits)
+	m := matrix.Create[int](1<<inBits, 1<<outBits)
+
+	for b := range m.Cols() {
+		spectrum := walshSpectrum(table, uint(b))
+		for a, w := range spectrum {
+			m.Set(a, b, w/2)
+		}
+	}
+
+	return &LAT{
+		Table:   m,
+		InBits:  inBits,
+		OutBits: outBits,
+	}
+}
+
+// maxAbs returns the largest absolute entry of the table for a non-zero output
+// mask
+func (l *LAT) maxAbs() int {
+	best := 0
+	for a := range l.Table.Rows() {
+		for b := 1; b < l.Table.Cols(); b++ {
+			v := l.Table.Get(a, b)
+			best = max(best, v, -v)
+		}
+	}
+	return best
+}
+
+// Linearity returns the largest absolute value in the Walsh spectra of all
+// non-trivial component functions, which is twice the largest absolute entry
+// of the table for a non-zero output mask.
+func (l *LAT) Linearity() int {
+	return 2 * l.maxAbs()
+}
+
+// Nonlinearity returns the smallest Hamming distance between any non-trivial
+// component function and an affine function.
+func (l *LAT) Nonlinearity() int {
+	return l.Table.Rows()/2 - l.maxAbs()
+}
+
+// MaxBias returns the largest absolute bias of any linear approximation with a
+// non-zero output mask.
+func (l *LAT) MaxBias() float64 {
+	return float64(l.maxAbs()) / float64(l.Table.Rows())
+}
@@ -0,0 +1,69 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"git.omicron.one/playground/cryptography/analysis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLATPresent(t *testing.T) {
+	lat := analysis.NewLAT(lookup(presentSBox), 4, 4)
+	assert.Equal(t, 4, lat.InBits)
+	assert.Equal(t, 4, lat.OutBits)
+	assert.Equal(t, 16, lat.Table.Rows())
+	assert.Equal(t, 16, lat.Table.Cols())
+
+	// Compare against the definition
+	for a := range uint(16) {
+		for b := range uint(16) {
+			count := 0
+			for x := range uint(16) {
+				if parity(a&x) == parity(b&presentSBox[x]) {
+					count++
+				}
+			}
+			assert.Equal(t, count-8, lat.Table.Get(int(a), int(b)))
+		}
+	}
+
+	assert.Equal(t, 8, lat.Linearity())
+	assert.Equal(t, 4, lat.Nonlinearity())
+	assert.Equal(t, 0.25, lat.MaxBias())
+}
+
+func TestLATAES(t *testing.T) {
+	lat := analysis.NewLAT(lookup(aesSBox()), 8, 8)
+
+	assert.Equal(t, 128, lat.Table.Get(0, 0))
+	assert.Equal(t, 32, lat.Linearity())
+	assert.Equal(t, 112, lat.Nonlinearity())
+	assert.Equal(t, 1.0/16, lat.MaxBias())
+}
+
+func TestLATLinear(t *testing.T) {
+	lat := analysis.NewLAT(func(x uint) uint {
+		return x ^ x>>1
+	}, 6, 6)
+
+	assert.Equal(t, 64, lat.Linearity())
+	assert.Equal(t, 0, lat.Nonlinearity())
+	assert.Equal(t, 0.5, lat.MaxBias())
+}
+
+func TestLATInvalid(t *testing.T) {
+	assert.PanicsWithValue(t, analysis.ErrInvalidBits, func() {
+		analysis.NewLAT(lookup(presentSBox), 4, 0)
+	})
+	assert.PanicsWithValue(t, analysis.ErrOutputOutOfRange, func() {
+		analysis.NewLAT(lookup(presentSBox), 4, 2)
+	})
+}
+
+func parity(x uint) int {
+	n := 0
+	for ; x != 0; x &= x - 1 {
+		n ^= 1
+	}
+	return n
+}
@@ -0,0 +1,223 @@
+package analysis
+
+import (
+	"math/bits"
+
+	"git.omicron.one/playground/cryptography/cipher"
+)
+
+// Mask is a pair of linear masks on the two words of an ARX state
+type Mask struct {
+	X uint64 `json:"x"`
+	Y uint64 `json:"y"`
+}
+
+// LinearTrail is a linear characteristic through a number of ARX rounds. Masks
+// holds the input mask of every round followed by the output mask of the last
+// round. Weights holds the correlation weight, -log2 of the absolute
+// correlation, of every round.
+type LinearTrail struct {
+	Masks   []Mask `json:"masks"`
+	Weights []int  `json:"weights"`
+}
+
+// Rounds returns the number of rounds covered by the trail
+func (t *LinearTrail) Rounds() int {
+	return len(t.Weights)
+}
+
+// Weight returns the total correlation weight of the trail
+func (t *LinearTrail) Weight() int {
+	w := 0
+	for _, weight := range t.Weights {
+		w += weight
+	}
+	return w
+}
+
+// Log2Correlation returns the base 2 logarithm of the absolute correlation of
+// the trail
+func (t *LinearTrail) Log2Correlation() float64 {
+	return -float64(t.Weight())
+}
+
+// AddCorrelationWeight returns the correlation weight of the linear
+// approximation u·(x + y) = v·x ^ w·y of addition modulo 2^n, using Wallén's
+// characterisation: with z_i the parity of bits i+1 and up of u ^ v ^ w, the
+// approximation has a non-zero correlation iff u ^ v and u ^ w are both
+// covered by z, and the absolute correlation is 2^-HW(z). The second return
+// value is false if the correlation is zero.
+func AddCorrelationWeight(u, v, w uint64, n int) (int, bool) {
+	mask := uint64(1)<<n - 1
+	if n == 64 {
+		mask = ^uint64(0)
+	}
+	u, v, w = u&mask, v&mask, w&mask
+
+	z := carryParity(u ^ v ^ w)
+	if (u^v)&^z != 0 || (u^w)&^z != 0 {
+		return 0, false
+	}
+	return bits.OnesCount64(z), true
+}
+
+// carryParity returns z where bit i is the parity of the bits above i in t
+func carryParity(t uint64) uint64 {
+	// Suffix XOR towards the least significant bit, excluding the bit itself
+	z := t >> 1
+	z ^= z >> 1
+	z ^= z >> 2
+	z ^= z >> 4
+	z ^= z >> 8
+	z ^= z >> 16
+	z ^= z >> 32
+	return z
+}
+
+// SearchLinearTrails searches for optimal linear trails through 1 up to the
+// given number of rounds of the ARX round function with Matsui's
+// branch-and-bound algorithm, evaluating the modular addition with
+// AddCorrelationWeight. Returns the best trail found for every number of
+// rounds, starting with one round. Only small word sizes and round numbers are
+// feasible.
+//
+// Panics with ErrInvalidBits or ErrInvalidRotation if the parameters are
+// invalid and with cipher.ErrInvalidRounds if rounds < 1.
+func SearchLinearTrails(p ARXParameters, rounds int) []*LinearTrail {
+	p.check()
+	if rounds < 1 {
+		panic(cipher.ErrInvalidRounds)
+	}
+
+	s := &linearSearch{
+		p:      p,
+		bounds: make([]int, rounds+1),
+	}
+
+	trails := make([]*LinearTrail, 0, rounds)
+	for r := 1; r <= rounds; r++ {
+		s.rounds = r
+		s.masks = make([]Mask, r+1)
+		s.weights = make([]int, r)
+		s.u = make([]uint64, r)
+		s.v = make([]uint64, r)
+		s.w = make([]uint64, r)
+		s.found = nil
+
+		for s.budget = s.bounds[r-1]; s.found == nil; s.budget++ {
+			s.enumerate(0, s.p.WordSize-1, true, 0, 0, 0, 0, 0)
+		}
+		s.bounds[r] = s.found.Weight()
+		trails = append(trails, s.found)
+	}
+	return trails
+}
+
+// linearSearch tracks the masks of the modular addition in every round: u on
+// the sum, v on the rotated x word and w on the y word. The masks of the
+// first two rounds are chosen freely, as the mask on the y word between them
+// is not constrained by anything else. All later rounds follow from the
+// previous masks.
+type linearSearch struct {
+	p       ARXParameters
+	rounds  int
+	budget  int
+	bounds  []int
+	masks   []Mask
+	weights []int
+	u, v, w []uint64
+	found   *LinearTrail
+}
+
+// enumerate chooses the masks of round r bit by bit starting at the most
+// significant bit i. If free is false the mask v is fixed. parity is the
+// parity of the higher bits of u ^ v ^ w and weight the weight of all rounds
+// so far.
+func (s *linearSearch) enumerate(r, i int, free bool, u, v, w, parity uint64, weight int) {
+	if s.found != nil || weight+s.bounds[s.rounds-r-1] > s.budget {
+		return
+	}
+	if i < 0 {
+		s.u[r], s.v[r], s.w[r] = u, v, w
+		s.weights[r] = weight
+		s.next(r)
+		return
+	}
+
+	vi := v >> i & 1
+	if parity == 0 {
+		// All masks must agree on this bit
+		for b := range uint64(2) {
+			if !free && b != vi {
+				continue
+			}
+			bit := b << i
+			s.enumerate(r, i-1, free, u|bit, v|bit, w|bit, parity^b, weight)
+		}
+		return
+	}
+
+	for bits := range uint64(8) {
+		ui, wi, bvi := bits&1, bits>>1&1, bits>>2
+		if !free && bvi != vi {
+			continue
+		}
+		s.enumerate(r, i-1, free, u|ui<<i, v|bvi<<i, w|wi<<i, parity^ui^bvi^wi, weight+1)
+	}
+}
+
+// next derives the masks after round r and continues with the next round
+func (s *linearSearch) next(r int) {
+	p := s.p
+	switch {
+	case s.rounds == 1:
+		// The y mask after the only round is free, zero is the simplest
+		s.masks[0] = Mask{X: p.rotateLeft(s.v[0], p.Alpha), Y: s.w[0]}
+		s.masks[1] = Mask{X: s.u[0]}
+		if s.masks[0] == (Mask{}) {
+			return
+		}
+		s.record()
+		return
+	case r == 0:
+		s.enumerate(1, p.WordSize-1, true, 0, 0, 0, 0, s.weights[0])
+		return
+	case r == 1:
+		// The free masks of the first two rounds fix the masks before them
+		x1 := p.rotateLeft(s.v[1], p.Alpha)
+		y1 := s.u[0] ^ x1
+		s.masks[1] = Mask{X: x1, Y: y1}
+		s.masks[0] = Mask{
+			X: p.rotateLeft(s.v[0], p.Alpha),
+			Y: s.w[0] ^ p.rotateRight(y1, p.Beta),
+		}
+		if s.masks[0] == (Mask{}) {
+			return
+		}
+	}
+
+	y := p.rotateLeft(s.masks[r].Y^s.w[r], p.Beta)
+	s.masks[r+1] = Mask{X: s.u[r] ^ y, Y: y}
+	if r+1 == s.rounds {
+		s.record()
+		return
+	}
+
+	v := p.rotateRight(s.masks[r+1].X, p.Alpha)
+	s.enumerate(r+1, p.WordSize-1, false, 0, v, 0, 0, s.weights[r])
+}
+
+// record stores the current trail, converting the cumulative weights to
+// weights per round
+func (s *linearSearch) record() {
+	weights := make([]int, s.rounds)
+	previous := 0
+	for r, w := range s.weights {
+		weights[r] = w - previous
+		previous = w
+	}
+	s.found = &LinearTrail{
+		Masks:   append([]Mask(nil), s.masks...),
+		Weights: weights,
+	}
+}
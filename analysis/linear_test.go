@@ -0,0 +1,103 @@
+package analysis_test
+
+import (
+	"math/bits"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/analysis"
+	"git.omicron.one/playground/cryptography/cipher"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddCorrelationWeight(t *testing.T) {
+	// Compare against the exact correlations for 4-bit words
+	const n = 4
+	for u := range uint64(1 << n) {
+		for v := range uint64(1 << n) {
+			for w := range uint64(1 << n) {
+				sum := 0
+				for x := range uint64(1 << n) {
+					for y := range uint64(1 << n) {
+						z := (x + y) & (1<<n - 1)
+						if bits.OnesCount64(u&z^v&x^w&y)%2 == 0 {
+							sum++
+						} else {
+							sum--
+						}
+					}
+				}
+				weight, ok := analysis.AddCorrelationWeight(u, v, w, n)
+				if sum == 0 {
+					assert.False(t, ok, "u=%x v=%x w=%x", u, v, w)
+					continue
+				}
+				assert.True(t, ok, "u=%x v=%x w=%x", u, v, w)
+				assert.Equal(t, 1<<(2*n), max(sum, -sum)<<weight, "u=%x v=%x w=%x", u, v, w)
+			}
+		}
+	}
+
+	w, ok := analysis.AddCorrelationWeight(1, 1, 1, 64)
+	assert.True(t, ok)
+	assert.Equal(t, 0, w)
+	w, ok = analysis.AddCorrelationWeight(1<<63, 1<<63, 1<<63, 64)
+	assert.True(t, ok)
+	assert.Equal(t, 63, w)
+	_, ok = analysis.AddCorrelationWeight(1<<63, 0, 0, 64)
+	assert.False(t, ok)
+}
+
+func testLinearTrailWeights(t *testing.T, p analysis.ARXParameters, expected []int) []*analysis.LinearTrail {
+	t.Helper()
+
+	rotateLeft := func(x uint64, r int) uint64 {
+		return (x<<r | x>>(p.WordSize-r)) & (1<<p.WordSize - 1)
+	}
+	rotateRight := func(x uint64, r int) uint64 {
+		return rotateLeft(x, p.WordSize-r)
+	}
+
+	trails := analysis.SearchLinearTrails(p, len(expected))
+	assert.Len(t, trails, len(expected))
+	for i, trail := range trails {
+		assert.Equal(t, i+1, trail.Rounds())
+		assert.Len(t, trail.Masks, i+2)
+		assert.Equal(t, expected[i], trail.Weight())
+		assert.Equal(t, -float64(expected[i]), trail.Log2Correlation())
+		assert.NotEqual(t, analysis.Mask{}, trail.Masks[0])
+
+		// Every round must be a valid approximation with the recorded weight
+		for r, weight := range trail.Weights {
+			in, out := trail.Masks[r], trail.Masks[r+1]
+			u := out.X ^ out.Y
+			v := rotateRight(in.X, p.Alpha)
+			w := in.Y ^ rotateRight(out.Y, p.Beta)
+			cw, ok := analysis.AddCorrelationWeight(u, v, w, p.WordSize)
+			assert.True(t, ok)
+			assert.Equal(t, weight, cw)
+		}
+	}
+	return trails
+}
+
+// The optimal weights are given in "Automatic Search of Linear Trails in ARX
+// with Applications to SPECK and Chaskey" by Liu, Wang and Rijmen
+func TestSearchLinearTrailsSpeck32(t *testing.T) {
+	testLinearTrailWeights(t, analysis.Speck32ARX, []int{0, 0, 1, 3, 5})
+}
+
+func TestSearchLinearTrailsSpeck128(t *testing.T) {
+	testLinearTrailWeights(t, analysis.Speck128ARX, []int{0, 0, 1})
+}
+
+func TestSearchLinearTrailsInvalid(t *testing.T) {
+	assert.PanicsWithValue(t, analysis.ErrInvalidBits, func() {
+		analysis.SearchLinearTrails(analysis.ARXParameters{WordSize: 0, Alpha: 8, Beta: 3}, 1)
+	})
+	assert.PanicsWithValue(t, analysis.ErrInvalidRotation, func() {
+		analysis.SearchLinearTrails(analysis.ARXParameters{WordSize: 16, Alpha: 16, Beta: 3}, 1)
+	})
+	assert.PanicsWithValue(t, cipher.ErrInvalidRounds, func() {
+		analysis.SearchLinearTrails(analysis.Speck32ARX, 0)
+	})
+}
@@ -0,0 +1,45 @@
+package analysis
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// runParallel performs the jobs 0 to n-1 with a number of goroutines, or
+// runtime.GOMAXPROCS(0) if workers is zero. Every goroutine calls worker once
+// to obtain the function that performs its jobs, which allows per goroutine
+// state such as matrix.Accumulator shards. Every job is performed exactly
+// once, so jobs may write to distinct elements of a shared matrix without
+// locking. A goroutine skips its remaining jobs after the first error.
+// Returns the errors of all goroutines joined.
+func runParallel(workers, n int, worker func() func(job int) error) error {
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, n)
+
+	errs := make([]error, workers)
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			do := worker()
+			for job := range jobs {
+				if errs[w] == nil {
+					errs[w] = do(job)
+				}
+			}
+		}()
+	}
+	for job := range n {
+		jobs <- job
+	}
+	close(jobs)
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,207 @@
+package analysis
+
+import (
+	"math/bits"
+
+	"git.omicron.one/playground/cryptography/cipher"
+)
+
+// Difference is a pair of XOR differences of the two words of an ARX state
+type Difference struct {
+	X uint64 `json:"x"`
+	Y uint64 `json:"y"`
+}
+
+// DifferentialTrail is a characteristic through a number of ARX rounds.
+// Differences holds the input difference of every round followed by the
+// output difference of the last round. Weights holds the weight, -log2 of the
+// probability, of every round.
+type DifferentialTrail struct {
+	Differences []Difference `json:"differences"`
+	Weights     []int        `json:"weights"`
+}
+
+// Rounds returns the number of rounds covered by the trail
+func (t *DifferentialTrail) Rounds() int {
+	return len(t.Weights)
+}
+
+// Weight returns the total weight of the trail
+func (t *DifferentialTrail) Weight() int {
+	w := 0
+	for _, weight := range t.Weights {
+		w += weight
+	}
+	return w
+}
+
+// Log2Probability returns the base 2 logarithm of the probability of the trail
+// assuming independent rounds
+func (t *DifferentialTrail) Log2Probability() float64 {
+	return -float64(t.Weight())
+}
+
+// AddDifferentialWeight returns the weight of the XOR differential
+// (a, b -> c) through addition modulo 2^n, using the formula of Lipmaa and
+// Moriai. The probability of the differential is 2^-weight. The second return
+// value is false if the differential is impossible.
+func AddDifferentialWeight(a, b, c uint64, n int) (int, bool) {
+	mask := uint64(1)<<n - 1
+	if n == 64 {
+		mask = ^uint64(0)
+	}
+	a, b, c = a&mask, b&mask, c&mask
+
+	if eq(a<<1, b<<1, c<<1)&(a^b^c^(b<<1))&mask != 0 {
+		return 0, false
+	}
+	return bits.OnesCount64(^eq(a, b, c) & (mask >> 1)), true
+}
+
+// eq returns the bits where x, y and z are all equal
+func eq(x, y, z uint64) uint64 {
+	return (^x ^ y) & (^x ^ z)
+}
+
+// SearchDifferentialTrails searches for optimal differential trails through
+// 1 up to the given number of rounds of the ARX round function. It uses
+// Matsui's branch-and-bound algorithm where the best weights found for fewer
+// rounds bound the search for more rounds, and the modular addition is
+// evaluated with AddDifferentialWeight. Returns the best trail found for every
+// number of rounds, starting with one round. Only small word sizes and round
+// numbers are feasible.
+//
+// Panics with ErrInvalidBits or ErrInvalidRotation if the parameters are
+// invalid and with cipher.ErrInvalidRounds if rounds < 1.
+func SearchDifferentialTrails(p ARXParameters, rounds int) []*DifferentialTrail {
+	p.check()
+	if rounds < 1 {
+		panic(cipher.ErrInvalidRounds)
+	}
+
+	s := &trailSearch{
+		p:      p,
+		bounds: make([]int, rounds+1),
+	}
+
+	trails := make([]*DifferentialTrail, 0, rounds)
+	for r := 1; r <= rounds; r++ {
+		s.rounds = r
+		s.diffs = make([]Difference, r+1)
+		s.weights = make([]int, r)
+		s.found = nil
+
+		for s.budget = s.bounds[r-1]; s.found == nil; s.budget++ {
+			s.searchFirst(0, 0, 0, 0, 0, true)
+		}
+		s.bounds[r] = s.found.Weight()
+		trails = append(trails, s.found)
+	}
+	return trails
+}
+
+type trailSearch struct {
+	p       ARXParameters
+	rounds  int
+	budget  int
+	bounds  []int
+	diffs   []Difference
+	weights []int
+	found   *DifferentialTrail
+}
+
+// searchFirst enumerates the differentials (a, b -> c) of the addition in the
+// first round bit by bit, starting at the least significant bit. a is the
+// rotated x difference and b the y difference. prevEq tells whether the
+// previous bits of a, b and c were equal, which constrains the current bit.
+func (s *trailSearch) searchFirst(i int, a, b, c uint64, w int, prevEq bool) {
+	if s.found != nil || w+s.bounds[s.rounds-1] > s.budget {
+		return
+	}
+	n := s.p.WordSize
+	if i == n {
+		if a == 0 && b == 0 {
+			return
+		}
+		s.diffs[0] = Difference{X: s.p.rotateLeft(a, s.p.Alpha), Y: b}
+		s.next(0, b, c, w)
+		return
+	}
+
+	var prevB uint64
+	if i > 0 {
+		prevB = b >> (i - 1) & 1
+	}
+	for bits := range uint64(8) {
+		ai, bi, ci := bits&1, bits>>1&1, bits>>2
+		if prevEq && ai^bi^ci != prevB {
+			continue
+		}
+		allEq := ai == bi && bi == ci
+		weight := w
+		if !allEq && i < n-1 {
+			weight++
+		}
+		s.searchFirst(i+1, a|ai<<i, b|bi<<i, c|ci<<i, weight, allEq)
+	}
+}
+
+// next records round r with output difference c of the addition and weight w
+// of all rounds so far, and continues with the next round
+func (s *trailSearch) next(r int, b, c uint64, w int) {
+	s.weights[r] = w
+	if r > 0 {
+		s.weights[r] -= s.totalWeight(r)
+	}
+	s.diffs[r+1] = Difference{X: c, Y: s.p.rotateLeft(b, s.p.Beta) ^ c}
+
+	if r+1 == s.rounds {
+		s.found = &DifferentialTrail{
+			Differences: append([]Difference(nil), s.diffs...),
+			Weights:     append([]int(nil), s.weights...),
+		}
+		return
+	}
+
+	in := s.diffs[r+1]
+	s.searchRound(r+1, 0, s.p.rotateRight(in.X, s.p.Alpha), in.Y, 0, w, true)
+}
+
+// searchRound enumerates the output differences c of the addition in round r
+// bit by bit for fixed input differences a and b
+func (s *trailSearch) searchRound(r, i int, a, b, c uint64, w int, prevEq bool) {
+	if s.found != nil || w+s.bounds[s.rounds-r-1] > s.budget {
+		return
+	}
+	n := s.p.WordSize
+	if i == n {
+		s.next(r, b, c, w)
+		return
+	}
+
+	ai, bi := a>>i&1, b>>i&1
+	var prevB uint64
+	if i > 0 {
+		prevB = b >> (i - 1) & 1
+	}
+	for ci := range uint64(2) {
+		if prevEq && ai^bi^ci != prevB {
+			continue
+		}
+		allEq := ai == bi && bi == ci
+		weight := w
+		if !allEq && i < n-1 {
+			weight++
+		}
+		s.searchRound(r, i+1, a, b, c|ci<<i, weight, allEq)
+	}
+}
+
+// totalWeight returns the sum of the weights of the first r rounds
+func (s *trailSearch) totalWeight(r int) int {
+	w := 0
+	for _, weight := range s.weights[:r] {
+		w += weight
+	}
+	return w
+}
@@ -0,0 +1,136 @@
+package analysis_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/analysis"
+	"git.omicron.one/playground/cryptography/cipher"
+	"git.omicron.one/playground/cryptography/cipher/speck/impl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddDifferentialWeight(t *testing.T) {
+	// Compare against the exact probabilities for 4-bit words
+	const n = 4
+	for a := range uint64(1 << n) {
+		for b := range uint64(1 << n) {
+			counts := make([]int, 1<<n)
+			for x := range uint64(1 << n) {
+				for y := range uint64(1 << n) {
+					c := ((x + y) ^ ((x ^ a) + (y ^ b))) & (1<<n - 1)
+					counts[c]++
+				}
+			}
+			for c, count := range counts {
+				w, ok := analysis.AddDifferentialWeight(a, b, uint64(c), n)
+				if count == 0 {
+					assert.False(t, ok)
+					continue
+				}
+				assert.True(t, ok)
+				assert.Equal(t, 1<<(2*n), count<<w, "a=%x b=%x c=%x", a, b, c)
+			}
+		}
+	}
+
+	w, ok := analysis.AddDifferentialWeight(1<<63, 1<<63, 0, 64)
+	assert.True(t, ok)
+	assert.Equal(t, 0, w)
+	w, ok = analysis.AddDifferentialWeight(1, 0, 1, 64)
+	assert.True(t, ok)
+	assert.Equal(t, 1, w)
+}
+
+func testTrailWeights(t *testing.T, p analysis.ARXParameters, expected []int) []*analysis.DifferentialTrail {
+	t.Helper()
+
+	trails := analysis.SearchDifferentialTrails(p, len(expected))
+	assert.Len(t, trails, len(expected))
+	for i, trail := range trails {
+		assert.Equal(t, i+1, trail.Rounds())
+		assert.Len(t, trail.Differences, i+2)
+		assert.Equal(t, expected[i], trail.Weight())
+		assert.Equal(t, -float64(expected[i]), trail.Log2Probability())
+		assert.NotEqual(t, analysis.Difference{}, trail.Differences[0])
+
+		// Every round must be a valid differential with the recorded weight
+		for r, weight := range trail.Weights {
+			in, out := trail.Differences[r], trail.Differences[r+1]
+			a := (in.X>>p.Alpha | in.X<<(p.WordSize-p.Alpha)) & (1<<p.WordSize - 1)
+			w, ok := analysis.AddDifferentialWeight(a, in.Y, out.X, p.WordSize)
+			assert.True(t, ok)
+			assert.Equal(t, weight, w)
+		}
+	}
+	return trails
+}
+
+// The optimal weights are given in "Automatic Search for Differential Trails
+// in ARX Ciphers" by Biryukov and Velichkov
+func TestSearchDifferentialTrailsSpeck32(t *testing.T) {
+	trails := testTrailWeights(t, analysis.Speck32ARX, []int{0, 1, 3, 5, 9})
+
+	// Check the probability of the trail through the implementation
+	rng := rand.New(rand.NewPCG(1, 2))
+	trail := trails[2]
+	in, out := trail.Differences[0], trail.Differences[3]
+	const samples = 1 << 14
+	hits := 0
+	for range samples {
+		x1, x2 := uint16(rng.Uint32()), uint16(rng.Uint32())
+		y1, y2 := x1^uint16(in.X), x2^uint16(in.Y)
+		for range trail.Rounds() {
+			k := uint16(rng.Uint32())
+			x1, x2 = impl.Round32(k, x1, x2)
+			y1, y2 = impl.Round32(k, y1, y2)
+		}
+		if x1^y1 == uint16(out.X) && x2^y2 == uint16(out.Y) {
+			hits++
+		}
+	}
+	assert.GreaterOrEqual(t, float64(hits)/samples, 0.8/float64(uint(1)<<trail.Weight()))
+}
+
+func TestSearchDifferentialTrailsSpeck64(t *testing.T) {
+	testTrailWeights(t, analysis.Speck64ARX, []int{0, 1, 3, 6})
+}
+
+func TestSearchDifferentialTrailsSpeck128(t *testing.T) {
+	trails := testTrailWeights(t, analysis.Speck128ARX, []int{0, 1, 3})
+
+	// Check the probability of the trail through the implementation
+	rng := rand.New(rand.NewPCG(3, 4))
+	trail := trails[2]
+	in, out := trail.Differences[0], trail.Differences[3]
+	const samples = 1 << 14
+	hits := 0
+	for range samples {
+		x1, x2 := rng.Uint64(), rng.Uint64()
+		y1, y2 := x1^in.X, x2^in.Y
+		for range trail.Rounds() {
+			k := rng.Uint64()
+			x1, x2 = impl.Round128(k, x1, x2)
+			y1, y2 = impl.Round128(k, y1, y2)
+		}
+		if x1^y1 == out.X && x2^y2 == out.Y {
+			hits++
+		}
+	}
+	assert.GreaterOrEqual(t, float64(hits)/samples, 0.8/float64(uint(1)<<trail.Weight()))
+}
+
+func TestSearchDifferentialTrailsInvalid(t *testing.T) {
+	assert.PanicsWithValue(t, analysis.ErrInvalidBits, func() {
+		analysis.SearchDifferentialTrails(analysis.ARXParameters{WordSize: 65, Alpha: 8, Beta: 3}, 1)
+	})
+	assert.PanicsWithValue(t, analysis.ErrInvalidRotation, func() {
+		analysis.SearchDifferentialTrails(analysis.ARXParameters{WordSize: 16, Alpha: 16, Beta: 3}, 1)
+	})
+	assert.PanicsWithValue(t, analysis.ErrInvalidRotation, func() {
+		analysis.SearchDifferentialTrails(analysis.ARXParameters{WordSize: 16, Alpha: 7, Beta: -1}, 1)
+	})
+	assert.PanicsWithValue(t, cipher.ErrInvalidRounds, func() {
+		analysis.SearchDifferentialTrails(analysis.Speck32ARX, 0)
+	})
+}
@@ -0,0 +1,53 @@
+package analysis
+
+import "math/bits"
+
+// WalshHadamard performs an in-place fast Walsh-Hadamard transform of v. After
+// the transform v[a] holds the sum over all x of v[x] * (-1)^(a·x), where a·x
+// is the parity of a & x. Applying the transform twice multiplies every value
+// by len(v).
+//
+// Panics with ErrInvalidLength if the length of v is not a power of two.
+func WalshHadamard(v []int) {
+	n := len(v)
+	if n == 0 || n&(n-1) != 0 {
+		panic(ErrInvalidLength)
+	}
+
+	for h := 1; h < n; h <<= 1 {
+		for i := 0; i < n; i += h << 1 {
+			for j := i; j < i+h; j++ {
+				x, y := v[j], v[j+h]
+				v[j], v[j+h] = x+y, x-y
+			}
+		}
+	}
+}
+
+// WalshSpectrum returns the Walsh spectrum of the Boolean component function
+// x -> b·f(x) of f. Element a of the result is the sum over all inputs x of
+// (-1)^(b·f(x) ^ a·x).
+//
+// Panics with ErrInvalidBits if inBits or outBits is not between 1 and 16.
+// Panics with ErrOutputOutOfRange if f returns a value with more than outBits
+// bits.
+func WalshSpectrum(f func(uint) uint, inBits, outBits int, b uint) []int {
+	checkBits(inBits)
+	checkBits(outBits)
+
+	return walshSpectrum(tabulate(f, inBits, outBits), b)
+}
+
+func walshSpectrum(table []uint, b uint) []int {
+	v := make([]int, len(table))
+	for x, y := range table {
+		v[x] = 1 - 2*parity(b&y)
+	}
+	WalshHadamard(v)
+	return v
+}
+
+// parity returns the parity of the set bits of x
+func parity(x uint) int {
+	return bits.OnesCount(x) & 1
+}
@@ -0,0 +1,79 @@
+package analysis_test
+
+import (
+	"math/bits"
+	"slices"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/analysis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalshHadamard(t *testing.T) {
+	v := []int{1, 0, 1, 0, 0, 1, 1, 0}
+	analysis.WalshHadamard(v)
+	assert.Equal(t, []int{4, 2, 0, -2, 0, 2, 0, 2}, v)
+
+	// Compare against the definition
+	input := []int{3, -1, 4, 1, -5, 9, 2, -6, 5, 3, -5, 8, 9, -7, 9, 3}
+	expected := make([]int, len(input))
+	for a := range input {
+		for x, value := range input {
+			if bits.OnesCount(uint(a&x))&1 == 0 {
+				expected[a] += value
+			} else {
+				expected[a] -= value
+			}
+		}
+	}
+	actual := slices.Clone(input)
+	analysis.WalshHadamard(actual)
+	assert.Equal(t, expected, actual)
+
+	// The transform is its own inverse up to a factor of the length
+	analysis.WalshHadamard(actual)
+	for i := range actual {
+		assert.Equal(t, input[i]*len(input), actual[i])
+	}
+
+	single := []int{7}
+	analysis.WalshHadamard(single)
+	assert.Equal(t, []int{7}, single)
+}
+
+func TestWalshHadamardInvalid(t *testing.T) {
+	for _, n := range []int{0, 3, 6, 12} {
+		assert.PanicsWithValue(t, analysis.ErrInvalidLength, func() {
+			analysis.WalshHadamard(make([]int, n))
+		})
+	}
+}
+
+func TestWalshSpectrum(t *testing.T) {
+	// The spectrum of a linear component function is zero everywhere except
+	// at its own mask
+	spectrum := analysis.WalshSpectrum(func(x uint) uint {
+		return x
+	}, 4, 4, 0b1010)
+	for a, w := range spectrum {
+		if a == 0b1010 {
+			assert.Equal(t, 16, w)
+		} else {
+			assert.Zero(t, w)
+		}
+	}
+
+	// Parseval's relation holds for every component function
+	for b := range uint(16) {
+		spectrum = analysis.WalshSpectrum(lookup(presentSBox), 4, 4, b)
+		sum := 0
+		for _, w := range spectrum {
+			sum += w * w
+		}
+		assert.Equal(t, 16*16, sum)
+	}
+
+	assert.PanicsWithValue(t, analysis.ErrInvalidBits, func() {
+		analysis.WalshSpectrum(lookup(presentSBox), 0, 4, 1)
+	})
+}
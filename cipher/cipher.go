@@ -2,7 +2,11 @@ package cipher
 
 import "errors"
 
-var ErrInvalidKeyLength = errors.New("Invalid key length")
+var (
+	ErrInvalidKeyLength = errors.New("Invalid key length")
+	ErrInvalidRounds    = errors.New("Invalid number of rounds")
+	ErrOpen             = errors.New("Message authentication failed")
+)
 
 // A Block represents an implementation of a block cipher using block cipher
 // specific parameters.
@@ -18,3 +22,46 @@ type Block interface {
 	// Algorithm returns the name of the algorithm
 	Algorithm() string
 }
+
+// A BlockMode represents a block cipher running in a block-based mode (ECB,
+// CBC, ...).
+type BlockMode interface {
+	// BlockSize returns the mode's block size in bytes
+	BlockSize() int
+	// CryptBlocks encrypts or decrypts a number of blocks. The length of src
+	// must be a multiple of the block size and dst must be at least as large
+	// as src. dst and src must overlap entirely or not at all.
+	CryptBlocks(dst, src []byte)
+}
+
+// A Stream represents a stream cipher, or a block cipher running in a
+// streaming mode (CFB, OFB, CTR, ...).
+type Stream interface {
+	// XORKeyStream XORs each byte in src with a byte from the key stream and
+	// writes the result to dst. dst must be at least as large as src. dst and
+	// src must overlap entirely or not at all.
+	XORKeyStream(dst, src []byte)
+}
+
+// AEAD is a cipher mode providing authenticated encryption with associated
+// data.
+type AEAD interface {
+	// NonceSize returns the size of the nonce that must be passed to Seal
+	// and Open.
+	NonceSize() int
+	// Overhead returns the maximum difference between the lengths of a
+	// plaintext and its ciphertext.
+	Overhead() int
+	// Seal encrypts and authenticates plaintext, authenticates the additional
+	// data and appends the result to dst, returning the updated slice. The
+	// nonce must be NonceSize() bytes long and unique for all time, for a
+	// given key. To reuse plaintext's storage for the encrypted output, use
+	// plaintext[:0] as dst.
+	Seal(dst, nonce, plaintext, additionalData []byte) []byte
+	// Open decrypts and authenticates ciphertext, authenticates the
+	// additional data and, if successful, appends the resulting plaintext to
+	// dst, returning the updated slice. Returns ErrOpen if authentication
+	// fails. To reuse ciphertext's storage for the decrypted output, use
+	// ciphertext[:0] as dst.
+	Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error)
+}
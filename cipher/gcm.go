@@ -0,0 +1,257 @@
+package cipher
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	gcmBlockSize         = 16
+	gcmStandardNonceSize = 12
+	gcmStandardTagSize   = 16
+
+	// gcmMaxPlaintextSize is the largest plaintext for a single nonce, before
+	// the 32-bit counter would wrap around and reuse the key stream
+	gcmMaxPlaintextSize = (1<<32 - 2) * gcmBlockSize
+)
+
+var (
+	ErrInvalidBlockSize = errors.New("Invalid block size, expected 128 bits")
+	ErrInvalidNonceSize = errors.New("Invalid nonce size")
+	ErrInvalidTagSize   = errors.New("Invalid tag size")
+	ErrMessageTooLong   = errors.New("Message too long for GCM")
+)
+
+// gcmFieldElement is an element of GF(2^128) in the bit order used by GCM:
+// the most significant bit of hi is the coefficient of x^0.
+type gcmFieldElement struct {
+	hi, lo uint64
+}
+
+type gcm struct {
+	b         Block
+	nonceSize int
+	tagSize   int
+	h         gcmFieldElement
+}
+
+// NewGCM returns the given 128-bit block cipher wrapped in Galois Counter Mode
+// with the standard nonce size of 12 bytes and tag size of 16 bytes. A
+// single message can be at most 2^32 - 2 blocks long: Seal panics with
+// ErrMessageTooLong for longer plaintexts and Open returns ErrOpen for longer
+// ciphertexts.
+//
+// Returns ErrInvalidBlockSize if the block size of the cipher isn't 128 bits.
+func NewGCM(b Block) (AEAD, error) {
+	return NewGCMWithParameters(b, gcmStandardNonceSize, gcmStandardTagSize)
+}
+
+// NewGCMWithParameters returns the given 128-bit block cipher wrapped in
+// Galois Counter Mode with a custom nonce and tag size. Any nonce size above
+// zero is accepted, but only the standard 12 byte nonce avoids an extra GHASH
+// computation. Following NIST SP 800-38D the tag size must be 4, 8 or between
+// 12 and 16 bytes. Short tags should only be used if the risks are
+// understood.
+//
+// Returns ErrInvalidBlockSize, ErrInvalidNonceSize or ErrInvalidTagSize if
+// the parameters are not supported.
+func NewGCMWithParameters(b Block, nonceSize, tagSize int) (AEAD, error) {
+	if b.BlockSize() != gcmBlockSize {
+		return nil, ErrInvalidBlockSize
+	}
+	if nonceSize <= 0 {
+		return nil, ErrInvalidNonceSize
+	}
+	if tagSize != 4 && tagSize != 8 && (tagSize < 12 || tagSize > 16) {
+		return nil, ErrInvalidTagSize
+	}
+
+	var key [gcmBlockSize]byte
+	b.Encrypt(key[:], key[:])
+
+	return &gcm{
+		b:         b,
+		nonceSize: nonceSize,
+		tagSize:   tagSize,
+		h: gcmFieldElement{
+			hi: binary.BigEndian.Uint64(key[:8]),
+			lo: binary.BigEndian.Uint64(key[8:]),
+		},
+	}, nil
+}
+
+func (g *gcm) NonceSize() int {
+	return g.nonceSize
+}
+
+func (g *gcm) Overhead() int {
+	return g.tagSize
+}
+
+func (g *gcm) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != g.nonceSize {
+		panic(ErrInvalidNonceSize)
+	}
+	if !gcmValidLength(len(plaintext)) {
+		panic(ErrMessageTooLong)
+	}
+
+	ret, out := sliceForAppend(dst, len(plaintext)+g.tagSize)
+
+	var counter, tagMask [gcmBlockSize]byte
+	g.deriveCounter(&counter, nonce)
+	g.b.Encrypt(tagMask[:], counter[:])
+
+	gcmInc32(&counter)
+	g.counterCrypt(out, plaintext, &counter)
+
+	var tag [gcmBlockSize]byte
+	g.auth(&tag, out[:len(plaintext)], additionalData, &tagMask)
+	copy(out[len(plaintext):], tag[:g.tagSize])
+
+	return ret
+}
+
+func (g *gcm) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != g.nonceSize {
+		panic(ErrInvalidNonceSize)
+	}
+	if len(ciphertext) < g.tagSize || !gcmValidLength(len(ciphertext)-g.tagSize) {
+		return nil, ErrOpen
+	}
+
+	tag := ciphertext[len(ciphertext)-g.tagSize:]
+	ciphertext = ciphertext[:len(ciphertext)-g.tagSize]
+
+	var counter, tagMask [gcmBlockSize]byte
+	g.deriveCounter(&counter, nonce)
+	g.b.Encrypt(tagMask[:], counter[:])
+
+	var expectedTag [gcmBlockSize]byte
+	g.auth(&expectedTag, ciphertext, additionalData, &tagMask)
+
+	ret, out := sliceForAppend(dst, len(ciphertext))
+	if subtle.ConstantTimeCompare(expectedTag[:g.tagSize], tag) != 1 {
+		// Don't leak any data in case dst is reused by the caller
+		clear(out)
+		return nil, ErrOpen
+	}
+
+	gcmInc32(&counter)
+	g.counterCrypt(out, ciphertext, &counter)
+
+	return ret, nil
+}
+
+// gcmValidLength returns true if a plaintext of n bytes can be encrypted with
+// a single nonce
+func gcmValidLength(n int) bool {
+	return uint64(n) <= gcmMaxPlaintextSize
+}
+
+// deriveCounter computes the initial counter block J_0 from the nonce
+func (g *gcm) deriveCounter(counter *[gcmBlockSize]byte, nonce []byte) {
+	if len(nonce) == gcmStandardNonceSize {
+		copy(counter[:], nonce)
+		counter[gcmBlockSize-1] = 1
+		return
+	}
+
+	var y gcmFieldElement
+	g.update(&y, nonce)
+	y.lo ^= uint64(len(nonce)) * 8
+	g.mul(&y)
+	binary.BigEndian.PutUint64(counter[:8], y.hi)
+	binary.BigEndian.PutUint64(counter[8:], y.lo)
+}
+
+// counterCrypt encrypts src into dst using the block cipher in counter mode
+// with a 32-bit counter
+func (g *gcm) counterCrypt(dst, src []byte, counter *[gcmBlockSize]byte) {
+	var mask [gcmBlockSize]byte
+	for len(src) > 0 {
+		g.b.Encrypt(mask[:], counter[:])
+		gcmInc32(counter)
+
+		n := subtle.XORBytes(dst, src, mask[:])
+		dst = dst[n:]
+		src = src[n:]
+	}
+}
+
+// auth computes the GHASH of the additional data and ciphertext and masks it
+// with tagMask to produce the tag
+func (g *gcm) auth(out *[gcmBlockSize]byte, ciphertext, additionalData []byte, tagMask *[gcmBlockSize]byte) {
+	var y gcmFieldElement
+	g.update(&y, additionalData)
+	g.update(&y, ciphertext)
+
+	y.hi ^= uint64(len(additionalData)) * 8
+	y.lo ^= uint64(len(ciphertext)) * 8
+	g.mul(&y)
+
+	binary.BigEndian.PutUint64(out[:8], y.hi)
+	binary.BigEndian.PutUint64(out[8:], y.lo)
+	subtle.XORBytes(out[:], out[:], tagMask[:])
+}
+
+// update absorbs data into the GHASH state y. A partial final block is padded
+// with zeros.
+func (g *gcm) update(y *gcmFieldElement, data []byte) {
+	for len(data) > 0 {
+		var block [gcmBlockSize]byte
+		n := copy(block[:], data)
+		data = data[n:]
+
+		y.hi ^= binary.BigEndian.Uint64(block[:8])
+		y.lo ^= binary.BigEndian.Uint64(block[8:])
+		g.mul(y)
+	}
+}
+
+// mul sets y to y * H in GF(2^128). The multiplication uses masks instead of
+// branches or table lookups so the timing does not depend on y or H.
+func (g *gcm) mul(y *gcmFieldElement) {
+	var z gcmFieldElement
+	v := g.h
+
+	for i := range 128 {
+		var bit uint64
+		if i < 64 {
+			bit = (y.hi >> (63 - i)) & 1
+		} else {
+			bit = (y.lo >> (127 - i)) & 1
+		}
+		mask := -bit
+		z.hi ^= v.hi & mask
+		z.lo ^= v.lo & mask
+
+		// Multiply v by x, reducing with x^128 + x^7 + x^2 + x + 1
+		reduce := -(v.lo & 1)
+		v.lo = v.lo>>1 | v.hi<<63
+		v.hi = v.hi>>1 ^ (0xe1<<56)&reduce
+	}
+
+	*y = z
+}
+
+// gcmInc32 increments the rightmost 32 bits of the counter block modulo 2^32
+func gcmInc32(counter *[gcmBlockSize]byte) {
+	ctr := counter[gcmBlockSize-4:]
+	binary.BigEndian.PutUint32(ctr, binary.BigEndian.Uint32(ctr)+1)
+}
+
+// sliceForAppend takes a slice and a requested number of bytes. It returns a
+// slice with the contents of the given slice followed by that many bytes and a
+// second slice that aliases into it and contains only the extra bytes.
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
+}
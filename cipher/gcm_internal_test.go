@@ -0,0 +1,24 @@
+package cipher
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Messages at the limit can't be allocated in a test, so the length check is
+// tested directly
+func TestGCMValidLength(t *testing.T) {
+	assert.True(t, gcmValidLength(0))
+	assert.True(t, gcmValidLength(1<<20))
+
+	// At most 2^32 - 2 blocks, the counter of the first block is used for the
+	// tag and the counter may not wrap around
+	var maxSize uint64 = (1<<32 - 2) * 16
+	if maxSize >= math.MaxInt {
+		t.Skip("Messages can't exceed the limit on this platform")
+	}
+	assert.True(t, gcmValidLength(int(maxSize)))
+	assert.False(t, gcmValidLength(int(maxSize)+1))
+}
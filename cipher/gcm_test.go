@@ -0,0 +1,213 @@
+package cipher_test
+
+import (
+	"crypto/aes"
+	gocipher "crypto/cipher"
+	"slices"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/cipher"
+	"git.omicron.one/playground/cryptography/cipher/speck/impl"
+	. "git.omicron.one/playground/cryptography/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// aesBlock adapts the standard library AES implementation to cipher.Block so
+// GCM can be checked against published test vectors
+type aesBlock struct {
+	gocipher.Block
+}
+
+func (b aesBlock) Algorithm() string {
+	return "AES"
+}
+
+func newAES(t *testing.T, key []byte) aesBlock {
+	t.Helper()
+	b, err := aes.NewCipher(key)
+	assert.Nil(t, err)
+	return aesBlock{b}
+}
+
+func testGCMVector(t *testing.T, key, nonce, plaintext, additionalData, ciphertext, tag []byte) {
+	t.Helper()
+
+	aead, err := cipher.NewGCMWithParameters(newAES(t, key), len(nonce), 16)
+	assert.Nil(t, err)
+	assert.Equal(t, len(nonce), aead.NonceSize())
+	assert.Equal(t, 16, aead.Overhead())
+
+	expected := append(slices.Clone(ciphertext), tag...)
+	sealed := aead.Seal(nil, nonce, plaintext, additionalData)
+	assert.Equal(t, expected, sealed)
+
+	opened, err := aead.Open(nil, nonce, sealed, additionalData)
+	assert.Nil(t, err)
+	assert.Equal(t, len(plaintext), len(opened))
+	if len(plaintext) > 0 {
+		assert.Equal(t, plaintext, opened)
+	}
+}
+
+// Test vectors from "The Galois/Counter Mode of Operation (GCM)" by McGrew and
+// Viega, test cases 1 through 6
+func TestGCMVectors(t *testing.T) {
+	var (
+		key       = DeHex("feffe9928665731c6d6a8f9467308308")
+		nonce     = DeHex("cafebabefacedbaddecaf888")
+		ad        = DeHex("feedfacedeadbeeffeedfacedeadbeefabaddad2")
+		plaintext = DeHex("d9313225f88406e5a55909c5aff5269a86a7a9531534f7da2e4c303d8a318a72" +
+			"1c3c0c95956809532fcf0e2449a6b525b16aedf5aa0de657ba637b391aafd255")
+	)
+
+	testGCMVector(t,
+		make([]byte, 16), make([]byte, 12), nil, nil, nil,
+		DeHex("58e2fccefa7e3061367f1d57a4e7455a"))
+	testGCMVector(t,
+		make([]byte, 16), make([]byte, 12), make([]byte, 16), nil,
+		DeHex("0388dace60b6a392f328c2b971b2fe78"),
+		DeHex("ab6e47d42cec13bdf53a67b21257bddf"))
+	testGCMVector(t,
+		key, nonce, plaintext, nil,
+		DeHex("42831ec2217774244b7221b784d0d49ce3aa212f2c02a4e035c17e2329aca12e"+
+			"21d514b25466931c7d8f6a5aac84aa051ba30b396a0aac973d58e091473f5985"),
+		DeHex("4d5c2af327cd64a62cf35abd2ba6fab4"))
+	testGCMVector(t,
+		key, nonce, plaintext[:60], ad,
+		DeHex("42831ec2217774244b7221b784d0d49ce3aa212f2c02a4e035c17e2329aca12e"+
+			"21d514b25466931c7d8f6a5aac84aa051ba30b396a0aac973d58e091"),
+		DeHex("5bc94fbc3221a5db94fae95ae7121a47"))
+	testGCMVector(t,
+		key, DeHex("cafebabefacedbad"), plaintext[:60], ad,
+		DeHex("61353b4c2806934a777ff51fa22a4755699b2a714fcdc6f83766e5f97b6c7423"+
+			"73806900e49f24b22b097544d4896b424989b5e1ebac0f07c23f4598"),
+		DeHex("3612d2e79e3b0785561be14aaca2fccb"))
+	testGCMVector(t,
+		key, DeHex("9313225df88406e555909c5aff5269aa6a7a9538534f7da1e4c303d2a318a728"+
+			"c3c0c95156809539fcf0e2429a6b525416aedbf5a0de6a57a637b39b"), plaintext[:60], ad,
+		DeHex("8ce24998625615b603a033aca13fb894be9112a5c3a211a8ba262a3cca7e2ca7"+
+			"01e4a9a4fba43c90ccdcb281d48c7c6fd62875d2aca417034c34aee5"),
+		DeHex("619cc5aefffe0bfa462af43c1699d050"))
+}
+
+func TestGCMStandardLibrary(t *testing.T) {
+	b := newAES(t, DeHex("000102030405060708090a0b0c0d0e0f"))
+
+	for _, nonceSize := range []int{1, 8, 12, 16, 60} {
+		for _, tagSize := range []int{12, 13, 14, 15, 16} {
+			var reference gocipher.AEAD
+			var err error
+			if nonceSize == 12 {
+				reference, err = gocipher.NewGCMWithTagSize(b.Block, tagSize)
+			} else if tagSize == 16 {
+				reference, err = gocipher.NewGCMWithNonceSize(b.Block, nonceSize)
+			} else {
+				continue
+			}
+			assert.Nil(t, err)
+
+			aead, err := cipher.NewGCMWithParameters(b, nonceSize, tagSize)
+			assert.Nil(t, err)
+			assert.Equal(t, tagSize, aead.Overhead())
+
+			nonce := make([]byte, nonceSize)
+			for i := range nonce {
+				nonce[i] = byte(i + 7)
+			}
+			for _, n := range []int{0, 1, 15, 16, 17, 100} {
+				plaintext := make([]byte, n)
+				for i := range plaintext {
+					plaintext[i] = byte(i * 3)
+				}
+				ad := plaintext[:n/2]
+				expected := reference.Seal(nil, nonce, plaintext, ad)
+				assert.Equal(t, expected, aead.Seal(nil, nonce, plaintext, ad))
+			}
+		}
+	}
+}
+
+func TestGCMSpeck(t *testing.T) {
+	b, err := impl.New128(DeHex("0f0e0d0c0b0a09080706050403020100"))
+	assert.Nil(t, err)
+	aead, err := cipher.NewGCM(b)
+	assert.Nil(t, err)
+
+	nonce := DeHex("000102030405060708090a0b")
+	plaintext := []byte("The quick brown fox jumps over the lazy dog")
+	ad := []byte("header")
+
+	// In-place
+	buffer := slices.Clone(plaintext)
+	sealed := aead.Seal(buffer[:0], nonce, buffer, ad)
+	assert.Len(t, sealed, len(plaintext)+aead.Overhead())
+	assert.NotEqual(t, plaintext, sealed[:len(plaintext)])
+
+	opened, err := aead.Open(sealed[:0], nonce, sealed, ad)
+	assert.Nil(t, err)
+	assert.Equal(t, plaintext, opened)
+
+	// Appending to dst
+	prefix := []byte("prefix")
+	sealed = aead.Seal(slices.Clone(prefix), nonce, plaintext, ad)
+	assert.Equal(t, prefix, sealed[:len(prefix)])
+	opened, err = aead.Open(slices.Clone(prefix), nonce, sealed[len(prefix):], ad)
+	assert.Nil(t, err)
+	assert.Equal(t, append(slices.Clone(prefix), plaintext...), opened)
+}
+
+func TestGCMTampering(t *testing.T) {
+	b := newAES(t, DeHex("000102030405060708090a0b0c0d0e0f"))
+	aead, err := cipher.NewGCM(b)
+	assert.Nil(t, err)
+
+	nonce := make([]byte, aead.NonceSize())
+	sealed := aead.Seal(nil, nonce, []byte("attack at dawn"), []byte("ad"))
+
+	for i := range sealed {
+		tampered := slices.Clone(sealed)
+		tampered[i] ^= 0x01
+		opened, err := aead.Open(nil, nonce, tampered, []byte("ad"))
+		assert.ErrorIs(t, err, cipher.ErrOpen)
+		assert.Nil(t, opened)
+	}
+
+	_, err = aead.Open(nil, nonce, sealed, []byte("da"))
+	assert.ErrorIs(t, err, cipher.ErrOpen)
+
+	_, err = aead.Open(nil, nonce, sealed[:aead.Overhead()-1], []byte("ad"))
+	assert.ErrorIs(t, err, cipher.ErrOpen)
+}
+
+func TestGCMInvalidParameters(t *testing.T) {
+	b := newAES(t, DeHex("000102030405060708090a0b0c0d0e0f"))
+
+	for _, tagSize := range []int{0, 3, 5, 7, 9, 10, 11, 17} {
+		aead, err := cipher.NewGCMWithParameters(b, 12, tagSize)
+		assert.ErrorIs(t, err, cipher.ErrInvalidTagSize)
+		assert.Nil(t, aead)
+	}
+	for _, tagSize := range []int{4, 8, 12, 16} {
+		_, err := cipher.NewGCMWithParameters(b, 12, tagSize)
+		assert.Nil(t, err)
+	}
+
+	aead, err := cipher.NewGCMWithParameters(b, 0, 16)
+	assert.ErrorIs(t, err, cipher.ErrInvalidNonceSize)
+	assert.Nil(t, aead)
+
+	speck64, err := impl.New64(make([]byte, impl.KeySize64128))
+	assert.Nil(t, err)
+	aead, err = cipher.NewGCM(speck64)
+	assert.ErrorIs(t, err, cipher.ErrInvalidBlockSize)
+	assert.Nil(t, aead)
+
+	aead, err = cipher.NewGCM(b)
+	assert.Nil(t, err)
+	assert.PanicsWithValue(t, cipher.ErrInvalidNonceSize, func() {
+		aead.Seal(nil, make([]byte, 8), nil, nil)
+	})
+	assert.PanicsWithValue(t, cipher.ErrInvalidNonceSize, func() {
+		aead.Open(nil, make([]byte, 8), make([]byte, 16), nil)
+	})
+}
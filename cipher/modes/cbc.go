@@ -0,0 +1,79 @@
+package modes
+
+import (
+	"crypto/subtle"
+	"slices"
+
+	"git.omicron.one/playground/cryptography/cipher"
+)
+
+type cbc struct {
+	b         cipher.Block
+	blockSize int
+	iv        []byte
+	tmp       []byte
+}
+
+func newCBC(b cipher.Block, iv []byte) *cbc {
+	if len(iv) != b.BlockSize() {
+		panic(ErrInvalidIVLength)
+	}
+	return &cbc{
+		b:         b,
+		blockSize: b.BlockSize(),
+		iv:        slices.Clone(iv),
+		tmp:       make([]byte, b.BlockSize()),
+	}
+}
+
+type cbcEncrypter cbc
+
+// NewCBCEncrypter returns a BlockMode which encrypts in cipher block chaining
+// mode using the given Block. The length of iv must be the same as the Block's
+// block size.
+//
+// Panics with ErrInvalidIVLength if the IV has the wrong length.
+func NewCBCEncrypter(b cipher.Block, iv []byte) cipher.BlockMode {
+	return (*cbcEncrypter)(newCBC(b, iv))
+}
+
+func (x *cbcEncrypter) BlockSize() int {
+	return x.blockSize
+}
+
+func (x *cbcEncrypter) CryptBlocks(dst, src []byte) {
+	checkBlocks(dst, src, x.blockSize)
+	for i := 0; i < len(src); i += x.blockSize {
+		block := dst[i : i+x.blockSize]
+		subtle.XORBytes(block, src[i:i+x.blockSize], x.iv)
+		x.b.Encrypt(block, block)
+		copy(x.iv, block)
+	}
+}
+
+type cbcDecrypter cbc
+
+// NewCBCDecrypter returns a BlockMode which decrypts in cipher block chaining
+// mode using the given Block. The length of iv must be the same as the Block's
+// block size and must match the iv used to encrypt the data.
+//
+// Panics with ErrInvalidIVLength if the IV has the wrong length.
+func NewCBCDecrypter(b cipher.Block, iv []byte) cipher.BlockMode {
+	return (*cbcDecrypter)(newCBC(b, iv))
+}
+
+func (x *cbcDecrypter) BlockSize() int {
+	return x.blockSize
+}
+
+func (x *cbcDecrypter) CryptBlocks(dst, src []byte) {
+	checkBlocks(dst, src, x.blockSize)
+	for i := 0; i < len(src); i += x.blockSize {
+		block := dst[i : i+x.blockSize]
+		// Keep the ciphertext around, dst and src may be the same buffer
+		copy(x.tmp, src[i:i+x.blockSize])
+		x.b.Decrypt(block, src[i:i+x.blockSize])
+		subtle.XORBytes(block, block, x.iv)
+		x.iv, x.tmp = x.tmp, x.iv
+	}
+}
@@ -0,0 +1,73 @@
+package modes_test
+
+import (
+	gocipher "crypto/cipher"
+	"slices"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/cipher/modes"
+	. "git.omicron.one/playground/cryptography/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCBCVector(t *testing.T) {
+	b := newAES(t, sp80038aKey)
+	ciphertext := DeHex("7649abac8119b246cee98e9b12e9197d" +
+		"5086cb9b507219ee95db113a917678b2" +
+		"73bed6b8e3c1743b7116e69e22229516" +
+		"3ff1caa1681fac09120eca307586e1a7")
+
+	enc := modes.NewCBCEncrypter(b, sp80038aIV)
+	assert.Equal(t, 16, enc.BlockSize())
+	buffer := make([]byte, len(sp80038aPT))
+	enc.CryptBlocks(buffer, sp80038aPT)
+	assert.Equal(t, ciphertext, buffer)
+
+	dec := modes.NewCBCDecrypter(b, sp80038aIV)
+	assert.Equal(t, 16, dec.BlockSize())
+	dec.CryptBlocks(buffer, buffer)
+	assert.Equal(t, sp80038aPT, buffer)
+}
+
+func TestCBCStandardLibrary(t *testing.T) {
+	b := newSpeck(t)
+	iv := testData(b.BlockSize())
+	plaintext := testData(7 * b.BlockSize())
+
+	expected := make([]byte, len(plaintext))
+	gocipher.NewCBCEncrypter(referenceBlock{b}, iv).CryptBlocks(expected, plaintext)
+
+	// Encrypt in two calls to check the chaining state is kept
+	enc := modes.NewCBCEncrypter(b, iv)
+	buffer := slices.Clone(plaintext)
+	enc.CryptBlocks(buffer[:32], buffer[:32])
+	enc.CryptBlocks(buffer[32:], buffer[32:])
+	assert.Equal(t, expected, buffer)
+
+	dec := modes.NewCBCDecrypter(b, iv)
+	dec.CryptBlocks(buffer[:48], buffer[:48])
+	dec.CryptBlocks(buffer[48:], buffer[48:])
+	assert.Equal(t, plaintext, buffer)
+}
+
+func TestCBCInvalidIV(t *testing.T) {
+	b := newSpeck(t)
+	assert.PanicsWithValue(t, modes.ErrInvalidIVLength, func() {
+		modes.NewCBCEncrypter(b, make([]byte, 15))
+	})
+	assert.PanicsWithValue(t, modes.ErrInvalidIVLength, func() {
+		modes.NewCBCDecrypter(b, make([]byte, 17))
+	})
+}
+
+func TestCBCInvalidLengths(t *testing.T) {
+	b := newSpeck(t)
+	iv := make([]byte, b.BlockSize())
+
+	assert.PanicsWithValue(t, modes.ErrInputNotFullBlocks, func() {
+		modes.NewCBCEncrypter(b, iv).CryptBlocks(make([]byte, 32), make([]byte, 31))
+	})
+	assert.PanicsWithValue(t, modes.ErrOutputTooSmall, func() {
+		modes.NewCBCDecrypter(b, iv).CryptBlocks(make([]byte, 16), make([]byte, 32))
+	})
+}
@@ -0,0 +1,71 @@
+package modes
+
+import (
+	"crypto/subtle"
+	"slices"
+
+	"git.omicron.one/playground/cryptography/cipher"
+)
+
+type cfb struct {
+	b       cipher.Block
+	next    []byte
+	out     []byte
+	outUsed int
+	decrypt bool
+}
+
+// NewCFBEncrypter returns a Stream which encrypts with cipher feedback mode,
+// using the given Block. The feedback size equals the block size. The iv must
+// be the same length as the Block's block size.
+//
+// Panics with ErrInvalidIVLength if the IV has the wrong length.
+func NewCFBEncrypter(b cipher.Block, iv []byte) cipher.Stream {
+	return newCFB(b, iv, false)
+}
+
+// NewCFBDecrypter returns a Stream which decrypts with cipher feedback mode,
+// using the given Block. The iv must be the same length as the Block's block
+// size.
+//
+// Panics with ErrInvalidIVLength if the IV has the wrong length.
+func NewCFBDecrypter(b cipher.Block, iv []byte) cipher.Stream {
+	return newCFB(b, iv, true)
+}
+
+func newCFB(b cipher.Block, iv []byte, decrypt bool) cipher.Stream {
+	if len(iv) != b.BlockSize() {
+		panic(ErrInvalidIVLength)
+	}
+	return &cfb{
+		b:       b,
+		next:    slices.Clone(iv),
+		out:     make([]byte, b.BlockSize()),
+		outUsed: b.BlockSize(),
+		decrypt: decrypt,
+	}
+}
+
+func (x *cfb) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic(ErrOutputTooSmall)
+	}
+	for len(src) > 0 {
+		if x.outUsed == len(x.out) {
+			x.b.Encrypt(x.out, x.next)
+			x.outUsed = 0
+		}
+
+		if x.decrypt {
+			// The ciphertext is the feedback, save it before dst overwrites it
+			copy(x.next[x.outUsed:], src)
+		}
+		n := subtle.XORBytes(dst, src, x.out[x.outUsed:])
+		if !x.decrypt {
+			copy(x.next[x.outUsed:], dst[:n])
+		}
+		dst = dst[n:]
+		src = src[n:]
+		x.outUsed += n
+	}
+}
@@ -0,0 +1,70 @@
+package modes_test
+
+import (
+	gocipher "crypto/cipher"
+	"slices"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/cipher"
+	"git.omicron.one/playground/cryptography/cipher/modes"
+	. "git.omicron.one/playground/cryptography/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCFBVector(t *testing.T) {
+	b := newAES(t, sp80038aKey)
+	ciphertext := DeHex("3b3fd92eb72dad20333449f8e83cfb4a" +
+		"c8a64537a0b3a93fcde3cdad9f1ce58b" +
+		"26751f67a3cbb140b1808cf187a4f4df" +
+		"c04b05357c5d1c0eeac4c66f9ff7f2e6")
+
+	buffer := make([]byte, len(sp80038aPT))
+	modes.NewCFBEncrypter(b, sp80038aIV).XORKeyStream(buffer, sp80038aPT)
+	assert.Equal(t, ciphertext, buffer)
+
+	modes.NewCFBDecrypter(b, sp80038aIV).XORKeyStream(buffer, buffer)
+	assert.Equal(t, sp80038aPT, buffer)
+}
+
+func TestCFBStandardLibrary(t *testing.T) {
+	b := newSpeck(t)
+	iv := testData(b.BlockSize())
+	plaintext := testData(100)
+
+	expected := make([]byte, len(plaintext))
+	gocipher.NewCFBEncrypter(referenceBlock{b}, iv).XORKeyStream(expected, plaintext)
+
+	buffer := slices.Clone(plaintext)
+	modes.NewCFBEncrypter(b, iv).XORKeyStream(buffer, buffer)
+	assert.Equal(t, expected, buffer)
+
+	modes.NewCFBDecrypter(b, iv).XORKeyStream(buffer, buffer)
+	assert.Equal(t, plaintext, buffer)
+}
+
+func TestCFBChunks(t *testing.T) {
+	b := newSpeck(t)
+	iv := testData(b.BlockSize())
+
+	testStreamChunks(t, func() cipher.Stream {
+		return modes.NewCFBEncrypter(b, iv)
+	}, testData(200))
+
+	testStreamChunks(t, func() cipher.Stream {
+		return modes.NewCFBDecrypter(b, iv)
+	}, testData(200))
+}
+
+func TestCFBInvalid(t *testing.T) {
+	b := newSpeck(t)
+	assert.PanicsWithValue(t, modes.ErrInvalidIVLength, func() {
+		modes.NewCFBEncrypter(b, make([]byte, 8))
+	})
+	assert.PanicsWithValue(t, modes.ErrInvalidIVLength, func() {
+		modes.NewCFBDecrypter(b, nil)
+	})
+	assert.PanicsWithValue(t, modes.ErrOutputTooSmall, func() {
+		stream := modes.NewCFBEncrypter(b, make([]byte, b.BlockSize()))
+		stream.XORKeyStream(make([]byte, 3), make([]byte, 4))
+	})
+}
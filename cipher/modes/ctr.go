@@ -0,0 +1,61 @@
+package modes
+
+import (
+	"crypto/subtle"
+	"slices"
+
+	"git.omicron.one/playground/cryptography/cipher"
+)
+
+type ctr struct {
+	b       cipher.Block
+	ctr     []byte
+	out     []byte
+	outUsed int
+}
+
+// NewCTR returns a Stream which encrypts or decrypts using the given Block in
+// counter mode. The iv is the initial counter block and must be the same
+// length as the Block's block size. The whole block is incremented as a big
+// endian integer after each block of key stream.
+//
+// Panics with ErrInvalidIVLength if the IV has the wrong length.
+func NewCTR(b cipher.Block, iv []byte) cipher.Stream {
+	if len(iv) != b.BlockSize() {
+		panic(ErrInvalidIVLength)
+	}
+	return &ctr{
+		b:       b,
+		ctr:     slices.Clone(iv),
+		out:     make([]byte, b.BlockSize()),
+		outUsed: b.BlockSize(),
+	}
+}
+
+func (x *ctr) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic(ErrOutputTooSmall)
+	}
+	for len(src) > 0 {
+		if x.outUsed == len(x.out) {
+			x.b.Encrypt(x.out, x.ctr)
+			x.outUsed = 0
+			increment(x.ctr)
+		}
+
+		n := subtle.XORBytes(dst, src, x.out[x.outUsed:])
+		dst = dst[n:]
+		src = src[n:]
+		x.outUsed += n
+	}
+}
+
+// increment adds one to a big endian counter, wrapping around on overflow
+func increment(counter []byte) {
+	for i := len(counter) - 1; i >= 0; i-- {
+		counter[i]++
+		if counter[i] != 0 {
+			break
+		}
+	}
+}
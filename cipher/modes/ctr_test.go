@@ -0,0 +1,62 @@
+package modes_test
+
+import (
+	gocipher "crypto/cipher"
+	"slices"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/cipher"
+	"git.omicron.one/playground/cryptography/cipher/modes"
+	. "git.omicron.one/playground/cryptography/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCTRVector(t *testing.T) {
+	b := newAES(t, sp80038aKey)
+	iv := DeHex("f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff")
+	ciphertext := DeHex("874d6191b620e3261bef6864990db6ce" +
+		"9806f66b7970fdff8617187bb9fffdff" +
+		"5ae4df3edbd5d35e5b4f09020db03eab" +
+		"1e031dda2fbe03d1792170a0f3009cee")
+
+	buffer := make([]byte, len(sp80038aPT))
+	modes.NewCTR(b, iv).XORKeyStream(buffer, sp80038aPT)
+	assert.Equal(t, ciphertext, buffer)
+
+	modes.NewCTR(b, iv).XORKeyStream(buffer, buffer)
+	assert.Equal(t, sp80038aPT, buffer)
+}
+
+func TestCTRStandardLibrary(t *testing.T) {
+	b := newSpeck(t)
+	// Starts close to overflowing to check the counter wraps around
+	iv := DeHex("fffffffffffffffffffffffffffffffe")
+	plaintext := testData(100)
+
+	expected := make([]byte, len(plaintext))
+	gocipher.NewCTR(referenceBlock{b}, iv).XORKeyStream(expected, plaintext)
+
+	buffer := slices.Clone(plaintext)
+	modes.NewCTR(b, iv).XORKeyStream(buffer, buffer)
+	assert.Equal(t, expected, buffer)
+}
+
+func TestCTRChunks(t *testing.T) {
+	b := newSpeck(t)
+	iv := testData(b.BlockSize())
+
+	testStreamChunks(t, func() cipher.Stream {
+		return modes.NewCTR(b, iv)
+	}, testData(200))
+}
+
+func TestCTRInvalid(t *testing.T) {
+	b := newSpeck(t)
+	assert.PanicsWithValue(t, modes.ErrInvalidIVLength, func() {
+		modes.NewCTR(b, make([]byte, 8))
+	})
+	assert.PanicsWithValue(t, modes.ErrOutputTooSmall, func() {
+		stream := modes.NewCTR(b, make([]byte, b.BlockSize()))
+		stream.XORKeyStream(make([]byte, 3), make([]byte, 4))
+	})
+}
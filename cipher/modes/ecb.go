@@ -0,0 +1,58 @@
+package modes
+
+import "git.omicron.one/playground/cryptography/cipher"
+
+type ecb struct {
+	b         cipher.Block
+	blockSize int
+}
+
+type ecbEncrypter ecb
+
+// NewECBEncrypter returns a BlockMode which encrypts in electronic codebook
+// mode using the given Block. ECB encrypts identical plaintext blocks to
+// identical ciphertext blocks and should only be used for experiments.
+func NewECBEncrypter(b cipher.Block) cipher.BlockMode {
+	return &ecbEncrypter{b: b, blockSize: b.BlockSize()}
+}
+
+func (x *ecbEncrypter) BlockSize() int {
+	return x.blockSize
+}
+
+func (x *ecbEncrypter) CryptBlocks(dst, src []byte) {
+	checkBlocks(dst, src, x.blockSize)
+	for i := 0; i < len(src); i += x.blockSize {
+		x.b.Encrypt(dst[i:i+x.blockSize], src[i:i+x.blockSize])
+	}
+}
+
+type ecbDecrypter ecb
+
+// NewECBDecrypter returns a BlockMode which decrypts in electronic codebook
+// mode using the given Block.
+func NewECBDecrypter(b cipher.Block) cipher.BlockMode {
+	return &ecbDecrypter{b: b, blockSize: b.BlockSize()}
+}
+
+func (x *ecbDecrypter) BlockSize() int {
+	return x.blockSize
+}
+
+func (x *ecbDecrypter) CryptBlocks(dst, src []byte) {
+	checkBlocks(dst, src, x.blockSize)
+	for i := 0; i < len(src); i += x.blockSize {
+		x.b.Decrypt(dst[i:i+x.blockSize], src[i:i+x.blockSize])
+	}
+}
+
+// checkBlocks panics if src is not a whole number of blocks or if dst is too
+// small to hold the output
+func checkBlocks(dst, src []byte, blockSize int) {
+	if len(src)%blockSize != 0 {
+		panic(ErrInputNotFullBlocks)
+	}
+	if len(dst) < len(src) {
+		panic(ErrOutputTooSmall)
+	}
+}
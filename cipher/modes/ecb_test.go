@@ -0,0 +1,62 @@
+package modes_test
+
+import (
+	"slices"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/cipher/modes"
+	. "git.omicron.one/playground/cryptography/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestECBVector(t *testing.T) {
+	b := newAES(t, sp80038aKey)
+	ciphertext := DeHex("3ad77bb40d7a3660a89ecaf32466ef97" +
+		"f5d3d58503b9699de785895a96fdbaaf" +
+		"43b1cd7f598ece23881b00e3ed030688" +
+		"7b0c785e27e8ad3f8223207104725dd4")
+
+	enc := modes.NewECBEncrypter(b)
+	assert.Equal(t, 16, enc.BlockSize())
+	buffer := make([]byte, len(sp80038aPT))
+	enc.CryptBlocks(buffer, sp80038aPT)
+	assert.Equal(t, ciphertext, buffer)
+
+	dec := modes.NewECBDecrypter(b)
+	assert.Equal(t, 16, dec.BlockSize())
+	dec.CryptBlocks(buffer, buffer)
+	assert.Equal(t, sp80038aPT, buffer)
+}
+
+func TestECBInPlace(t *testing.T) {
+	b := newSpeck(t)
+	plaintext := testData(5 * b.BlockSize())
+
+	expected := make([]byte, len(plaintext))
+	modes.NewECBEncrypter(b).CryptBlocks(expected, plaintext)
+
+	buffer := slices.Clone(plaintext)
+	modes.NewECBEncrypter(b).CryptBlocks(buffer, buffer)
+	assert.Equal(t, expected, buffer)
+	modes.NewECBDecrypter(b).CryptBlocks(buffer, buffer)
+	assert.Equal(t, plaintext, buffer)
+}
+
+func TestECBInvalidLengths(t *testing.T) {
+	b := newSpeck(t)
+	enc := modes.NewECBEncrypter(b)
+	dec := modes.NewECBDecrypter(b)
+
+	assert.PanicsWithValue(t, modes.ErrInputNotFullBlocks, func() {
+		enc.CryptBlocks(make([]byte, 32), make([]byte, 17))
+	})
+	assert.PanicsWithValue(t, modes.ErrInputNotFullBlocks, func() {
+		dec.CryptBlocks(make([]byte, 32), make([]byte, 17))
+	})
+	assert.PanicsWithValue(t, modes.ErrOutputTooSmall, func() {
+		enc.CryptBlocks(make([]byte, 16), make([]byte, 32))
+	})
+	assert.PanicsWithValue(t, modes.ErrOutputTooSmall, func() {
+		dec.CryptBlocks(make([]byte, 16), make([]byte, 32))
+	})
+}
@@ -0,0 +1,12 @@
+// Package modes implements block cipher modes of operation on top of
+// cipher.Block. The API follows the conventions of the standard library's
+// crypto/cipher package.
+package modes
+
+import "errors"
+
+var (
+	ErrInvalidIVLength    = errors.New("IV length must equal block size")
+	ErrInputNotFullBlocks = errors.New("Input not full blocks")
+	ErrOutputTooSmall     = errors.New("Output smaller than input")
+)
@@ -0,0 +1,86 @@
+package modes_test
+
+import (
+	"crypto/aes"
+	gocipher "crypto/cipher"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/cipher"
+	"git.omicron.one/playground/cryptography/cipher/speck/impl"
+	. "git.omicron.one/playground/cryptography/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// aesBlock adapts the standard library AES implementation to cipher.Block so
+// that the modes can be checked against published test vectors
+type aesBlock struct {
+	gocipher.Block
+}
+
+func (b aesBlock) Algorithm() string {
+	return "AES"
+}
+
+func newAES(t *testing.T, key []byte) cipher.Block {
+	t.Helper()
+	b, err := aes.NewCipher(key)
+	assert.Nil(t, err)
+	return aesBlock{b}
+}
+
+func newSpeck(t *testing.T) *impl.Speck128 {
+	t.Helper()
+	ctx, err := impl.New128(DeHex("0f0e0d0c0b0a09080706050403020100"))
+	assert.Nil(t, err)
+	return ctx
+}
+
+// referenceBlock adapts a cipher.Block for use with the standard library
+// modes, which may pass buffers larger than a single block
+type referenceBlock struct {
+	cipher.Block
+}
+
+func (b referenceBlock) Encrypt(dst, src []byte) {
+	b.Block.Encrypt(dst[:b.BlockSize()], src[:b.BlockSize()])
+}
+
+func (b referenceBlock) Decrypt(dst, src []byte) {
+	b.Block.Decrypt(dst[:b.BlockSize()], src[:b.BlockSize()])
+}
+
+// Test vectors from NIST SP 800-38A, appendix F
+var (
+	sp80038aKey = DeHex("2b7e151628aed2a6abf7158809cf4f3c")
+	sp80038aIV  = DeHex("000102030405060708090a0b0c0d0e0f")
+	sp80038aPT  = DeHex("6bc1bee22e409f96e93d7e117393172a" +
+		"ae2d8a571e03ac9c9eb76fac45af8e51" +
+		"30c81c46a35ce411e5fbc1191a0a52ef" +
+		"f69f2445df4f9b17ad2b417be66c3710")
+)
+
+// testData returns n bytes of deterministic, non-repeating test data
+func testData(n int) []byte {
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = byte(i*31 + i>>8)
+	}
+	return data
+}
+
+// testStreamChunks encrypts data with two streams, one in a single call and
+// one in irregular chunks, and checks both produce the same output
+func testStreamChunks(t *testing.T, newStream func() cipher.Stream, data []byte) {
+	t.Helper()
+
+	expected := make([]byte, len(data))
+	newStream().XORKeyStream(expected, data)
+
+	stream := newStream()
+	actual := make([]byte, len(data))
+	for i, n := 0, 1; i < len(data); i, n = i+n, n+1 {
+		end := min(i+n, len(data))
+		stream.XORKeyStream(actual[i:end], data[i:end])
+	}
+	assert.Equal(t, expected, actual)
+}
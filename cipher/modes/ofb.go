@@ -0,0 +1,47 @@
+package modes
+
+import (
+	"crypto/subtle"
+	"slices"
+
+	"git.omicron.one/playground/cryptography/cipher"
+)
+
+type ofb struct {
+	b       cipher.Block
+	out     []byte
+	outUsed int
+}
+
+// NewOFB returns a Stream that encrypts or decrypts using the block cipher b
+// in output feedback mode. The iv must be the same length as the Block's block
+// size.
+//
+// Panics with ErrInvalidIVLength if the IV has the wrong length.
+func NewOFB(b cipher.Block, iv []byte) cipher.Stream {
+	if len(iv) != b.BlockSize() {
+		panic(ErrInvalidIVLength)
+	}
+	return &ofb{
+		b:       b,
+		out:     slices.Clone(iv),
+		outUsed: b.BlockSize(),
+	}
+}
+
+func (x *ofb) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic(ErrOutputTooSmall)
+	}
+	for len(src) > 0 {
+		if x.outUsed == len(x.out) {
+			x.b.Encrypt(x.out, x.out)
+			x.outUsed = 0
+		}
+
+		n := subtle.XORBytes(dst, src, x.out[x.outUsed:])
+		dst = dst[n:]
+		src = src[n:]
+		x.outUsed += n
+	}
+}
@@ -0,0 +1,60 @@
+package modes_test
+
+import (
+	gocipher "crypto/cipher"
+	"slices"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/cipher"
+	"git.omicron.one/playground/cryptography/cipher/modes"
+	. "git.omicron.one/playground/cryptography/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOFBVector(t *testing.T) {
+	b := newAES(t, sp80038aKey)
+	ciphertext := DeHex("3b3fd92eb72dad20333449f8e83cfb4a" +
+		"7789508d16918f03f53c52dac54ed825" +
+		"9740051e9c5fecf64344f7a82260edcc" +
+		"304c6528f659c77866a510d9c1d6ae5e")
+
+	buffer := make([]byte, len(sp80038aPT))
+	modes.NewOFB(b, sp80038aIV).XORKeyStream(buffer, sp80038aPT)
+	assert.Equal(t, ciphertext, buffer)
+
+	modes.NewOFB(b, sp80038aIV).XORKeyStream(buffer, buffer)
+	assert.Equal(t, sp80038aPT, buffer)
+}
+
+func TestOFBStandardLibrary(t *testing.T) {
+	b := newSpeck(t)
+	iv := testData(b.BlockSize())
+	plaintext := testData(100)
+
+	expected := make([]byte, len(plaintext))
+	gocipher.NewOFB(referenceBlock{b}, iv).XORKeyStream(expected, plaintext)
+
+	buffer := slices.Clone(plaintext)
+	modes.NewOFB(b, iv).XORKeyStream(buffer, buffer)
+	assert.Equal(t, expected, buffer)
+}
+
+func TestOFBChunks(t *testing.T) {
+	b := newSpeck(t)
+	iv := testData(b.BlockSize())
+
+	testStreamChunks(t, func() cipher.Stream {
+		return modes.NewOFB(b, iv)
+	}, testData(200))
+}
+
+func TestOFBInvalid(t *testing.T) {
+	b := newSpeck(t)
+	assert.PanicsWithValue(t, modes.ErrInvalidIVLength, func() {
+		modes.NewOFB(b, make([]byte, 8))
+	})
+	assert.PanicsWithValue(t, modes.ErrOutputTooSmall, func() {
+		stream := modes.NewOFB(b, make([]byte, b.BlockSize()))
+		stream.XORKeyStream(make([]byte, 3), make([]byte, 4))
+	})
+}
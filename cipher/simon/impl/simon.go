@@ -0,0 +1,73 @@
+// impl implements the Simon algorithm. This implementation should not be used
+// and instead the parent package should be used. The implementations exposes
+// all the internal details for testing and analysis.
+package impl
+
+// Z contains the five constant sequences z_0 through z_4 used by the Simon key
+// schedule. Each sequence has a period of 62 and is stored with its first
+// element in the most significant of the lower 62 bits, so that the binary
+// representation reads the same as the sequences in the paper.
+var Z = [5]uint64{
+	0b11111010001001010110000111001101111101000100101011000011100110,
+	0b10001110111110010011000010110101000111011111001001100001011010,
+	0b10101111011100000011010010011000101000010001111110010110110011,
+	0b11011011101011000110010111100000010010001010011100110100001111,
+	0b11010001111001101011011000100000010111000011001010010011101111,
+}
+
+// ZBit returns element i of the constant sequence z_j. The index wraps around
+// with the period of the sequence.
+func ZBit(j, i int) uint64 {
+	return (Z[j] >> (61 - i%62)) & 1
+}
+
+// The key schedule constant c = 2^n - 4 for each word size n
+const (
+	keyConstant16 = 1<<16 - 4
+	keyConstant24 = 1<<24 - 4
+	keyConstant32 = 1<<32 - 4
+	keyConstant48 = 1<<48 - 4
+	keyConstant64 = 1<<64 - 4
+)
+
+// uint24 decodes a big endian 24-bit word
+func uint24(b []byte) uint32 {
+	_ = b[2] // bounds check hint to compiler
+	return uint32(b[2]) | uint32(b[1])<<8 | uint32(b[0])<<16
+}
+
+// putUint24 encodes a 24-bit word in big endian byte order
+func putUint24(b []byte, v uint32) {
+	_ = b[2] // early bounds check
+	b[0] = byte(v >> 16)
+	b[1] = byte(v >> 8)
+	b[2] = byte(v)
+}
+
+// rotateLeft24 rotates a 24-bit word left by 0 <= k < 24 bits
+func rotateLeft24(x uint32, k int) uint32 {
+	return (x<<k | x>>(24-k)) & mask24
+}
+
+// uint48 decodes a big endian 48-bit word
+func uint48(b []byte) uint64 {
+	_ = b[5] // bounds check hint to compiler
+	return uint64(b[5]) | uint64(b[4])<<8 | uint64(b[3])<<16 |
+		uint64(b[2])<<24 | uint64(b[1])<<32 | uint64(b[0])<<40
+}
+
+// putUint48 encodes a 48-bit word in big endian byte order
+func putUint48(b []byte, v uint64) {
+	_ = b[5] // early bounds check
+	b[0] = byte(v >> 40)
+	b[1] = byte(v >> 32)
+	b[2] = byte(v >> 24)
+	b[3] = byte(v >> 16)
+	b[4] = byte(v >> 8)
+	b[5] = byte(v)
+}
+
+// rotateLeft48 rotates a 48-bit word left by 0 <= k < 48 bits
+func rotateLeft48(x uint64, k int) uint64 {
+	return (x<<k | x>>(48-k)) & mask48
+}
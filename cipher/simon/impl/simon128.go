@@ -0,0 +1,122 @@
+package impl
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"git.omicron.one/playground/cryptography/cipher"
+)
+
+const (
+	BlockSize128  = 128 / 8
+	KeySize128128 = 128 / 8
+	KeySize128192 = 192 / 8
+	KeySize128256 = 256 / 8
+	Rounds128128  = 68
+	Rounds128192  = 69
+	Rounds128256  = 72
+)
+
+type Simon128 struct {
+	Keys []uint64
+
+	keyWords int
+}
+
+func New128(key []byte) (*Simon128, error) {
+	var m int
+	var rounds int
+	var z int
+
+	// The key words are stored in the order k_0, k_1, ..., k_{m-1}. Like the
+	// test vectors in the paper the key bytes list k_{m-1} first.
+	switch len(key) {
+	case KeySize128128:
+		rounds = Rounds128128
+		m = 2
+		z = 2
+	case KeySize128192:
+		rounds = Rounds128192
+		m = 3
+		z = 3
+	case KeySize128256:
+		rounds = Rounds128256
+		m = 4
+		z = 4
+	default:
+		return nil, cipher.ErrInvalidKeyLength
+	}
+
+	ctx := &Simon128{
+		Keys:     make([]uint64, rounds),
+		keyWords: m,
+	}
+
+	for i := range m {
+		ctx.Keys[i] = binary.BigEndian.Uint64(key[(m-1-i)*8:])
+	}
+	for i := m; i < rounds; i++ {
+		tmp := bits.RotateLeft64(ctx.Keys[i-1], 64-3)
+		if m == 4 {
+			tmp ^= ctx.Keys[i-3]
+		}
+		tmp ^= bits.RotateLeft64(tmp, 64-1)
+		ctx.Keys[i] = keyConstant64 ^ uint64(ZBit(z, i-m)) ^ ctx.Keys[i-m] ^ tmp
+	}
+	return ctx, nil
+}
+
+func f128(x uint64) uint64 {
+	return (bits.RotateLeft64(x, 1) & bits.RotateLeft64(x, 8)) ^ bits.RotateLeft64(x, 2)
+}
+
+func Round128(k, x1, x2 uint64) (uint64, uint64) {
+	return x2 ^ f128(x1) ^ k, x1
+}
+
+func InverseRound128(k, x1, x2 uint64) (uint64, uint64) {
+	return x2, x1 ^ f128(x2) ^ k
+}
+
+func (ctx *Simon128) Encrypt(dst, src []byte) {
+	if len(dst) != BlockSize128 || len(src) != BlockSize128 {
+		panic("Incorrect blocksize, expected 128 bits")
+	}
+
+	x1 := binary.BigEndian.Uint64(src[:8])
+	x2 := binary.BigEndian.Uint64(src[8:])
+	for _, k := range ctx.Keys {
+		x1, x2 = Round128(k, x1, x2)
+	}
+	binary.BigEndian.PutUint64(dst[:8], x1)
+	binary.BigEndian.PutUint64(dst[8:], x2)
+}
+
+func (ctx *Simon128) Decrypt(dst, src []byte) {
+	if len(dst) != BlockSize128 || len(src) != BlockSize128 {
+		panic("Incorrect blocksize, expected 128 bits")
+	}
+	x1 := binary.BigEndian.Uint64(src[:8])
+	x2 := binary.BigEndian.Uint64(src[8:])
+	for i := len(ctx.Keys) - 1; i >= 0; i-- {
+		x1, x2 = InverseRound128(ctx.Keys[i], x1, x2)
+	}
+	binary.BigEndian.PutUint64(dst[:8], x1)
+	binary.BigEndian.PutUint64(dst[8:], x2)
+}
+
+func (ctx *Simon128) BlockSize() int {
+	return BlockSize128
+}
+
+func (ctx *Simon128) Algorithm() string {
+	switch ctx.keyWords {
+	case 2:
+		return "Simon128/128"
+	case 3:
+		return "Simon128/192"
+	case 4:
+		return "Simon128/256"
+	}
+	panic("unreachable")
+}
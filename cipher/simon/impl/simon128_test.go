@@ -0,0 +1,134 @@
+package impl_test
+
+import (
+	"slices"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/cipher"
+	"git.omicron.one/playground/cryptography/cipher/simon/impl"
+	. "git.omicron.one/playground/cryptography/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func testVector128(t *testing.T, key, plaintext, ciphertext []byte, bs int, name string) {
+	t.Helper()
+
+	buffer := make([]byte, len(plaintext))
+	ctx, err := impl.New128(key)
+	assert.Nil(t, err)
+	assert.NotNil(t, ctx)
+	assert.Equal(t, bs, ctx.BlockSize())
+	assert.Equal(t, name, ctx.Algorithm())
+
+	// Two buffers
+	pt := slices.Clone(plaintext)
+	ctx.Encrypt(buffer, pt)
+	assert.Equal(t, plaintext, pt)
+	assert.Equal(t, ciphertext, buffer)
+
+	clear(buffer)
+	ct := slices.Clone(ciphertext)
+	ctx.Decrypt(buffer, ct)
+	assert.Equal(t, ciphertext, ct)
+	assert.Equal(t, plaintext, buffer)
+
+	// In-place
+	copy(buffer, plaintext)
+	ctx.Encrypt(buffer, buffer)
+	assert.Equal(t, ciphertext, buffer)
+	ctx.Decrypt(buffer, buffer)
+	assert.Equal(t, plaintext, buffer)
+}
+
+func TestVector128128(t *testing.T) {
+	var (
+		key        = DeHex("0f0e0d0c0b0a09080706050403020100")
+		plaintext  = DeHex("63736564207372656c6c657661727420")
+		ciphertext = DeHex("49681b1e1e54fe3f65aa832af84e0bbc")
+		bs         = impl.BlockSize128
+		name       = "Simon128/128"
+	)
+	testVector128(t, key, plaintext, ciphertext, bs, name)
+}
+
+func TestVector128192(t *testing.T) {
+	var (
+		key        = DeHex("17161514131211100f0e0d0c0b0a09080706050403020100")
+		plaintext  = DeHex("206572656874206e6568772065626972")
+		ciphertext = DeHex("c4ac61effcdc0d4f6c9c8d6e2597b85b")
+		bs         = impl.BlockSize128
+		name       = "Simon128/192"
+	)
+	testVector128(t, key, plaintext, ciphertext, bs, name)
+}
+
+func TestVector128256(t *testing.T) {
+	var (
+		key        = DeHex("1f1e1d1c1b1a191817161514131211100f0e0d0c0b0a09080706050403020100")
+		plaintext  = DeHex("74206e69206d6f6f6d69732061207369")
+		ciphertext = DeHex("8d2b5579afc8a3a03bf72a87efe7b868")
+		bs         = impl.BlockSize128
+		name       = "Simon128/256"
+	)
+	testVector128(t, key, plaintext, ciphertext, bs, name)
+}
+
+func TestInvalidKey128(t *testing.T) {
+	ctx, err := impl.New128(DeHex("deadbeef"))
+	assert.ErrorIs(t, cipher.ErrInvalidKeyLength, err)
+	assert.Nil(t, ctx)
+}
+
+func TestDecryptBlockSize128(t *testing.T) {
+	ctx, err := impl.New128(DeHex("1f1e1d1c1b1a191817161514131211100f0e0d0c0b0a09080706050403020100"))
+	assert.Nil(t, err)
+	assert.NotNil(t, ctx)
+
+	assert.Panics(t, func() {
+		buffer := make([]byte, ctx.BlockSize()-1)
+		ctx.Decrypt(nil, buffer)
+	})
+	assert.Panics(t, func() {
+		buffer := make([]byte, ctx.BlockSize()-1)
+		ctx.Decrypt(buffer, nil)
+	})
+	assert.Panics(t, func() {
+		buffer := make([]byte, ctx.BlockSize()-1)
+		ctx.Decrypt(buffer, buffer)
+	})
+	assert.Panics(t, func() {
+		buffer := make([]byte, ctx.BlockSize()+1)
+		ctx.Decrypt(buffer, buffer)
+	})
+	assert.NotPanics(t, func() {
+		buffer := make([]byte, ctx.BlockSize())
+		ctx.Decrypt(buffer, buffer)
+	})
+}
+
+func TestEncryptBlockSize128(t *testing.T) {
+	ctx, err := impl.New128(DeHex("1f1e1d1c1b1a191817161514131211100f0e0d0c0b0a09080706050403020100"))
+	assert.Nil(t, err)
+	assert.NotNil(t, ctx)
+
+	assert.Panics(t, func() {
+		buffer := make([]byte, ctx.BlockSize()-1)
+		ctx.Encrypt(nil, buffer)
+	})
+	assert.Panics(t, func() {
+		buffer := make([]byte, ctx.BlockSize()-1)
+		ctx.Encrypt(buffer, nil)
+	})
+	assert.Panics(t, func() {
+		buffer := make([]byte, ctx.BlockSize()-1)
+		ctx.Encrypt(buffer, buffer)
+	})
+	assert.Panics(t, func() {
+		buffer := make([]byte, ctx.BlockSize()+1)
+		ctx.Encrypt(buffer, buffer)
+	})
+	assert.NotPanics(t, func() {
+		buffer := make([]byte, ctx.BlockSize())
+		ctx.Encrypt(buffer, buffer)
+	})
+}
@@ -0,0 +1,102 @@
+package impl
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"git.omicron.one/playground/cryptography/cipher"
+)
+
+const (
+	BlockSize32 = 32 / 8
+	KeySize3264 = 64 / 8
+	Rounds3264  = 32
+)
+
+type Simon32 struct {
+	Keys []uint16
+
+	keyWords int
+}
+
+func New32(key []byte) (*Simon32, error) {
+	var m int
+	var rounds int
+	var z int
+
+	// The key words are stored in the order k_0, k_1, ..., k_{m-1}. Like the
+	// test vectors in the paper the key bytes list k_{m-1} first.
+	switch len(key) {
+	case KeySize3264:
+		rounds = Rounds3264
+		m = 4
+		z = 0
+	default:
+		return nil, cipher.ErrInvalidKeyLength
+	}
+
+	ctx := &Simon32{
+		Keys:     make([]uint16, rounds),
+		keyWords: m,
+	}
+
+	for i := range m {
+		ctx.Keys[i] = binary.BigEndian.Uint16(key[(m-1-i)*2:])
+	}
+	for i := m; i < rounds; i++ {
+		tmp := bits.RotateLeft16(ctx.Keys[i-1], 16-3)
+		if m == 4 {
+			tmp ^= ctx.Keys[i-3]
+		}
+		tmp ^= bits.RotateLeft16(tmp, 16-1)
+		ctx.Keys[i] = keyConstant16 ^ uint16(ZBit(z, i-m)) ^ ctx.Keys[i-m] ^ tmp
+	}
+	return ctx, nil
+}
+
+func f32(x uint16) uint16 {
+	return (bits.RotateLeft16(x, 1) & bits.RotateLeft16(x, 8)) ^ bits.RotateLeft16(x, 2)
+}
+
+func Round32(k, x1, x2 uint16) (uint16, uint16) {
+	return x2 ^ f32(x1) ^ k, x1
+}
+
+func InverseRound32(k, x1, x2 uint16) (uint16, uint16) {
+	return x2, x1 ^ f32(x2) ^ k
+}
+
+func (ctx *Simon32) Encrypt(dst, src []byte) {
+	if len(dst) != BlockSize32 || len(src) != BlockSize32 {
+		panic("Incorrect blocksize, expected 32 bits")
+	}
+
+	x1 := binary.BigEndian.Uint16(src[:2])
+	x2 := binary.BigEndian.Uint16(src[2:])
+	for _, k := range ctx.Keys {
+		x1, x2 = Round32(k, x1, x2)
+	}
+	binary.BigEndian.PutUint16(dst[:2], x1)
+	binary.BigEndian.PutUint16(dst[2:], x2)
+}
+
+func (ctx *Simon32) Decrypt(dst, src []byte) {
+	if len(dst) != BlockSize32 || len(src) != BlockSize32 {
+		panic("Incorrect blocksize, expected 32 bits")
+	}
+	x1 := binary.BigEndian.Uint16(src[:2])
+	x2 := binary.BigEndian.Uint16(src[2:])
+	for i := len(ctx.Keys) - 1; i >= 0; i-- {
+		x1, x2 = InverseRound32(ctx.Keys[i], x1, x2)
+	}
+	binary.BigEndian.PutUint16(dst[:2], x1)
+	binary.BigEndian.PutUint16(dst[2:], x2)
+}
+
+func (ctx *Simon32) BlockSize() int {
+	return BlockSize32
+}
+
+func (ctx *Simon32) Algorithm() string {
+	return "Simon32/64"
+}
@@ -0,0 +1,115 @@
+package impl
+
+import (
+	"git.omicron.one/playground/cryptography/cipher"
+)
+
+const (
+	BlockSize48 = 48 / 8
+	KeySize4872 = 72 / 8
+	KeySize4896 = 96 / 8
+	Rounds4872  = 36
+	Rounds4896  = 36
+
+	mask24 = 1<<24 - 1
+)
+
+// Simon48 stores its 24-bit words in the lower bits of uint32 values. The
+// upper 8 bits of every word are always zero.
+type Simon48 struct {
+	Keys []uint32
+
+	keyWords int
+}
+
+func New48(key []byte) (*Simon48, error) {
+	var m int
+	var rounds int
+	var z int
+
+	// The key words are stored in the order k_0, k_1, ..., k_{m-1}. Like the
+	// test vectors in the paper the key bytes list k_{m-1} first.
+	switch len(key) {
+	case KeySize4872:
+		rounds = Rounds4872
+		m = 3
+		z = 0
+	case KeySize4896:
+		rounds = Rounds4896
+		m = 4
+		z = 1
+	default:
+		return nil, cipher.ErrInvalidKeyLength
+	}
+
+	ctx := &Simon48{
+		Keys:     make([]uint32, rounds),
+		keyWords: m,
+	}
+
+	for i := range m {
+		ctx.Keys[i] = uint24(key[(m-1-i)*3:])
+	}
+	for i := m; i < rounds; i++ {
+		tmp := rotateLeft24(ctx.Keys[i-1], 24-3)
+		if m == 4 {
+			tmp ^= ctx.Keys[i-3]
+		}
+		tmp ^= rotateLeft24(tmp, 24-1)
+		ctx.Keys[i] = keyConstant24 ^ uint32(ZBit(z, i-m)) ^ ctx.Keys[i-m] ^ tmp
+	}
+	return ctx, nil
+}
+
+func f48(x uint32) uint32 {
+	return (rotateLeft24(x, 1) & rotateLeft24(x, 8)) ^ rotateLeft24(x, 2)
+}
+
+func Round48(k, x1, x2 uint32) (uint32, uint32) {
+	return x2 ^ f48(x1) ^ k, x1
+}
+
+func InverseRound48(k, x1, x2 uint32) (uint32, uint32) {
+	return x2, x1 ^ f48(x2) ^ k
+}
+
+func (ctx *Simon48) Encrypt(dst, src []byte) {
+	if len(dst) != BlockSize48 || len(src) != BlockSize48 {
+		panic("Incorrect blocksize, expected 48 bits")
+	}
+
+	x1 := uint24(src[:3])
+	x2 := uint24(src[3:])
+	for _, k := range ctx.Keys {
+		x1, x2 = Round48(k, x1, x2)
+	}
+	putUint24(dst[:3], x1)
+	putUint24(dst[3:], x2)
+}
+
+func (ctx *Simon48) Decrypt(dst, src []byte) {
+	if len(dst) != BlockSize48 || len(src) != BlockSize48 {
+		panic("Incorrect blocksize, expected 48 bits")
+	}
+	x1 := uint24(src[:3])
+	x2 := uint24(src[3:])
+	for i := len(ctx.Keys) - 1; i >= 0; i-- {
+		x1, x2 = InverseRound48(ctx.Keys[i], x1, x2)
+	}
+	putUint24(dst[:3], x1)
+	putUint24(dst[3:], x2)
+}
+
+func (ctx *Simon48) BlockSize() int {
+	return BlockSize48
+}
+
+func (ctx *Simon48) Algorithm() string {
+	switch ctx.keyWords {
+	case 3:
+		return "Simon48/72"
+	case 4:
+		return "Simon48/96"
+	}
+	panic("unreachable")
+}
@@ -0,0 +1,114 @@
+package impl
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"git.omicron.one/playground/cryptography/cipher"
+)
+
+const (
+	BlockSize64  = 64 / 8
+	KeySize6496  = 96 / 8
+	KeySize64128 = 128 / 8
+	Rounds6496   = 42
+	Rounds64128  = 44
+)
+
+type Simon64 struct {
+	Keys []uint32
+
+	keyWords int
+}
+
+func New64(key []byte) (*Simon64, error) {
+	var m int
+	var rounds int
+	var z int
+
+	// The key words are stored in the order k_0, k_1, ..., k_{m-1}. Like the
+	// test vectors in the paper the key bytes list k_{m-1} first.
+	switch len(key) {
+	case KeySize6496:
+		rounds = Rounds6496
+		m = 3
+		z = 2
+	case KeySize64128:
+		rounds = Rounds64128
+		m = 4
+		z = 3
+	default:
+		return nil, cipher.ErrInvalidKeyLength
+	}
+
+	ctx := &Simon64{
+		Keys:     make([]uint32, rounds),
+		keyWords: m,
+	}
+
+	for i := range m {
+		ctx.Keys[i] = binary.BigEndian.Uint32(key[(m-1-i)*4:])
+	}
+	for i := m; i < rounds; i++ {
+		tmp := bits.RotateLeft32(ctx.Keys[i-1], 32-3)
+		if m == 4 {
+			tmp ^= ctx.Keys[i-3]
+		}
+		tmp ^= bits.RotateLeft32(tmp, 32-1)
+		ctx.Keys[i] = keyConstant32 ^ uint32(ZBit(z, i-m)) ^ ctx.Keys[i-m] ^ tmp
+	}
+	return ctx, nil
+}
+
+func f64(x uint32) uint32 {
+	return (bits.RotateLeft32(x, 1) & bits.RotateLeft32(x, 8)) ^ bits.RotateLeft32(x, 2)
+}
+
+func Round64(k, x1, x2 uint32) (uint32, uint32) {
+	return x2 ^ f64(x1) ^ k, x1
+}
+
+func InverseRound64(k, x1, x2 uint32) (uint32, uint32) {
+	return x2, x1 ^ f64(x2) ^ k
+}
+
+func (ctx *Simon64) Encrypt(dst, src []byte) {
+	if len(dst) != BlockSize64 || len(src) != BlockSize64 {
+		panic("Incorrect blocksize, expected 64 bits")
+	}
+
+	x1 := binary.BigEndian.Uint32(src[:4])
+	x2 := binary.BigEndian.Uint32(src[4:])
+	for _, k := range ctx.Keys {
+		x1, x2 = Round64(k, x1, x2)
+	}
+	binary.BigEndian.PutUint32(dst[:4], x1)
+	binary.BigEndian.PutUint32(dst[4:], x2)
+}
+
+func (ctx *Simon64) Decrypt(dst, src []byte) {
+	if len(dst) != BlockSize64 || len(src) != BlockSize64 {
+		panic("Incorrect blocksize, expected 64 bits")
+	}
+	x1 := binary.BigEndian.Uint32(src[:4])
+	x2 := binary.BigEndian.Uint32(src[4:])
+	for i := len(ctx.Keys) - 1; i >= 0; i-- {
+		x1, x2 = InverseRound64(ctx.Keys[i], x1, x2)
+	}
+	binary.BigEndian.PutUint32(dst[:4], x1)
+	binary.BigEndian.PutUint32(dst[4:], x2)
+}
+
+func (ctx *Simon64) BlockSize() int {
+	return BlockSize64
+}
+
+func (ctx *Simon64) Algorithm() string {
+	switch ctx.keyWords {
+	case 3:
+		return "Simon64/96"
+	case 4:
+		return "Simon64/128"
+	}
+	panic("unreachable")
+}
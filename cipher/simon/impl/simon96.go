@@ -0,0 +1,115 @@
+package impl
+
+import (
+	"git.omicron.one/playground/cryptography/cipher"
+)
+
+const (
+	BlockSize96  = 96 / 8
+	KeySize9696  = 96 / 8
+	KeySize96144 = 144 / 8
+	Rounds9696   = 52
+	Rounds96144  = 54
+
+	mask48 = 1<<48 - 1
+)
+
+// Simon96 stores its 48-bit words in the lower bits of uint64 values. The
+// upper 16 bits of every word are always zero.
+type Simon96 struct {
+	Keys []uint64
+
+	keyWords int
+}
+
+func New96(key []byte) (*Simon96, error) {
+	var m int
+	var rounds int
+	var z int
+
+	// The key words are stored in the order k_0, k_1, ..., k_{m-1}. Like the
+	// test vectors in the paper the key bytes list k_{m-1} first.
+	switch len(key) {
+	case KeySize9696:
+		rounds = Rounds9696
+		m = 2
+		z = 2
+	case KeySize96144:
+		rounds = Rounds96144
+		m = 3
+		z = 3
+	default:
+		return nil, cipher.ErrInvalidKeyLength
+	}
+
+	ctx := &Simon96{
+		Keys:     make([]uint64, rounds),
+		keyWords: m,
+	}
+
+	for i := range m {
+		ctx.Keys[i] = uint48(key[(m-1-i)*6:])
+	}
+	for i := m; i < rounds; i++ {
+		tmp := rotateLeft48(ctx.Keys[i-1], 48-3)
+		if m == 4 {
+			tmp ^= ctx.Keys[i-3]
+		}
+		tmp ^= rotateLeft48(tmp, 48-1)
+		ctx.Keys[i] = keyConstant48 ^ uint64(ZBit(z, i-m)) ^ ctx.Keys[i-m] ^ tmp
+	}
+	return ctx, nil
+}
+
+func f96(x uint64) uint64 {
+	return (rotateLeft48(x, 1) & rotateLeft48(x, 8)) ^ rotateLeft48(x, 2)
+}
+
+func Round96(k, x1, x2 uint64) (uint64, uint64) {
+	return x2 ^ f96(x1) ^ k, x1
+}
+
+func InverseRound96(k, x1, x2 uint64) (uint64, uint64) {
+	return x2, x1 ^ f96(x2) ^ k
+}
+
+func (ctx *Simon96) Encrypt(dst, src []byte) {
+	if len(dst) != BlockSize96 || len(src) != BlockSize96 {
+		panic("Incorrect blocksize, expected 96 bits")
+	}
+
+	x1 := uint48(src[:6])
+	x2 := uint48(src[6:])
+	for _, k := range ctx.Keys {
+		x1, x2 = Round96(k, x1, x2)
+	}
+	putUint48(dst[:6], x1)
+	putUint48(dst[6:], x2)
+}
+
+func (ctx *Simon96) Decrypt(dst, src []byte) {
+	if len(dst) != BlockSize96 || len(src) != BlockSize96 {
+		panic("Incorrect blocksize, expected 96 bits")
+	}
+	x1 := uint48(src[:6])
+	x2 := uint48(src[6:])
+	for i := len(ctx.Keys) - 1; i >= 0; i-- {
+		x1, x2 = InverseRound96(ctx.Keys[i], x1, x2)
+	}
+	putUint48(dst[:6], x1)
+	putUint48(dst[6:], x2)
+}
+
+func (ctx *Simon96) BlockSize() int {
+	return BlockSize96
+}
+
+func (ctx *Simon96) Algorithm() string {
+	switch ctx.keyWords {
+	case 2:
+		return "Simon96/96"
+	case 3:
+		return "Simon96/144"
+	}
+	panic("unreachable")
+}
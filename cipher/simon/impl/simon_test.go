@@ -0,0 +1,21 @@
+package impl_test
+
+import (
+	"testing"
+
+	"git.omicron.one/playground/cryptography/cipher/simon/impl"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZBit(t *testing.T) {
+	// First and last elements of z_0 = 11111010...00110
+	assert.Equal(t, uint64(1), impl.ZBit(0, 0))
+	assert.Equal(t, uint64(0), impl.ZBit(0, 5))
+	assert.Equal(t, uint64(0), impl.ZBit(0, 61))
+
+	for j := range impl.Z {
+		for i := range 62 {
+			assert.Equal(t, impl.ZBit(j, i), impl.ZBit(j, i+62))
+		}
+	}
+}
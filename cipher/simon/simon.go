@@ -0,0 +1,112 @@
+// Package simon implements the Simon block cipher as defined in
+// https://eprint.iacr.org/2013/404.pdf.
+package simon
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"git.omicron.one/playground/cryptography/cipher"
+	"git.omicron.one/playground/cryptography/cipher/simon/impl"
+)
+
+var ErrUnknownParameters = errors.New("Unknown parameters")
+
+type SimonParameters int
+
+const (
+	Simon3264 = iota + 1
+	Simon4872
+	Simon4896
+	Simon6496
+	Simon64128
+	Simon9696
+	Simon96144
+	Simon128128
+	Simon128192
+	Simon128256
+)
+
+var keySizes = []int{
+	0,  // unused
+	8,  // Simon3264
+	9,  // Simon4872
+	12, // Simon4896
+	12, // Simon6496
+	16, // Simon64128
+	12, // Simon9696
+	18, // Simon96144
+	16, // Simon128128
+	24, // Simon128192
+	32, // Simon128256
+}
+
+var names = []string{
+	"", // unused
+	"Simon32/64",
+	"Simon48/72",
+	"Simon48/96",
+	"Simon64/96",
+	"Simon64/128",
+	"Simon96/96",
+	"Simon96/144",
+	"Simon128/128",
+	"Simon128/192",
+	"Simon128/256",
+}
+
+// String returns the name of the parameter set as used by Algorithm, for
+// example "Simon128/256".
+func (param SimonParameters) String() string {
+	if param <= 0 || int(param) >= len(names) {
+		return fmt.Sprintf("SimonParameters(%d)", int(param))
+	}
+	return names[param]
+}
+
+// ParseParameters returns the parameter set with the given name, for example
+// "Simon128/256". The comparison is case insensitive.
+// Returns ErrUnknownParameters if no parameter set has the given name.
+func ParseParameters(name string) (SimonParameters, error) {
+	for i := 1; i < len(names); i++ {
+		if strings.EqualFold(name, names[i]) {
+			return SimonParameters(i), nil
+		}
+	}
+	return 0, ErrUnknownParameters
+}
+
+// KeySize returns the key size in bytes for the given parameters.
+// Panics if the parameters are invalid.
+func KeySize(param SimonParameters) int {
+	if param <= 0 || int(param) >= len(keySizes) {
+		panic("Invalid parameters")
+	}
+	return keySizes[param]
+}
+
+// New creates a new simon block cipher context.
+// Returns the created block cipher or an error.
+func New(key []byte, param SimonParameters) (cipher.Block, error) {
+	if param <= 0 || int(param) >= len(keySizes) {
+		panic("Invalid parameters")
+	}
+	keySize := keySizes[param]
+	if len(key) != keySize {
+		return nil, cipher.ErrInvalidKeyLength
+	}
+	switch param {
+	case Simon3264:
+		return impl.New32(key)
+	case Simon4872, Simon4896:
+		return impl.New48(key)
+	case Simon6496, Simon64128:
+		return impl.New64(key)
+	case Simon9696, Simon96144:
+		return impl.New96(key)
+	case Simon128128, Simon128192, Simon128256:
+		return impl.New128(key)
+	}
+	panic("unreachable")
+}
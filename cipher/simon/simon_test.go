@@ -0,0 +1,147 @@
+package simon_test
+
+import (
+	"testing"
+
+	"git.omicron.one/playground/cryptography/cipher"
+	"git.omicron.one/playground/cryptography/cipher/simon"
+	"github.com/stretchr/testify/assert"
+)
+
+func testKey(param simon.SimonParameters) []byte {
+	switch param {
+	case simon.Simon3264:
+		return make([]byte, 64/8)
+	case simon.Simon4872:
+		return make([]byte, 72/8)
+	case simon.Simon4896, simon.Simon6496, simon.Simon9696:
+		return make([]byte, 96/8)
+	case simon.Simon64128, simon.Simon128128:
+		return make([]byte, 128/8)
+	case simon.Simon96144:
+		return make([]byte, 144/8)
+	case simon.Simon128192:
+		return make([]byte, 192/8)
+	case simon.Simon128256:
+		return make([]byte, 256/8)
+	}
+	panic("unreachable")
+}
+
+func TestNew(t *testing.T) {
+	params := []simon.SimonParameters{
+		simon.Simon3264,
+		simon.Simon4872,
+		simon.Simon4896,
+		simon.Simon6496,
+		simon.Simon64128,
+		simon.Simon9696,
+		simon.Simon96144,
+		simon.Simon128128,
+		simon.Simon128192,
+		simon.Simon128256,
+	}
+
+	for _, param := range params {
+		key := testKey(param)
+		ctx, err := simon.New(key, param)
+		assert.Nil(t, err)
+		assert.NotNil(t, ctx)
+	}
+}
+
+func TestAlgorithm(t *testing.T) {
+	names := map[simon.SimonParameters]string{
+		simon.Simon3264:   "Simon32/64",
+		simon.Simon4872:   "Simon48/72",
+		simon.Simon4896:   "Simon48/96",
+		simon.Simon6496:   "Simon64/96",
+		simon.Simon64128:  "Simon64/128",
+		simon.Simon9696:   "Simon96/96",
+		simon.Simon96144:  "Simon96/144",
+		simon.Simon128128: "Simon128/128",
+		simon.Simon128192: "Simon128/192",
+		simon.Simon128256: "Simon128/256",
+	}
+
+	for param, name := range names {
+		ctx, err := simon.New(testKey(param), param)
+		assert.Nil(t, err)
+		assert.Equal(t, name, ctx.Algorithm())
+	}
+}
+
+func TestParameterNames(t *testing.T) {
+	params := []simon.SimonParameters{
+		simon.Simon3264,
+		simon.Simon4872,
+		simon.Simon4896,
+		simon.Simon6496,
+		simon.Simon64128,
+		simon.Simon9696,
+		simon.Simon96144,
+		simon.Simon128128,
+		simon.Simon128192,
+		simon.Simon128256,
+	}
+	for _, param := range params {
+		key := testKey(param)
+		assert.Equal(t, len(key), simon.KeySize(param))
+
+		ctx, err := simon.New(key, param)
+		assert.Nil(t, err)
+		assert.Equal(t, ctx.Algorithm(), param.String())
+
+		parsed, err := simon.ParseParameters(param.String())
+		assert.Nil(t, err)
+		assert.Equal(t, param, parsed)
+	}
+
+	parsed, err := simon.ParseParameters("simon64/128")
+	assert.Nil(t, err)
+	assert.Equal(t, simon.SimonParameters(simon.Simon64128), parsed)
+
+	_, err = simon.ParseParameters("Simon128")
+	assert.ErrorIs(t, err, simon.ErrUnknownParameters)
+	_, err = simon.ParseParameters("Speck128/256")
+	assert.ErrorIs(t, err, simon.ErrUnknownParameters)
+
+	assert.Equal(t, "SimonParameters(0)", simon.SimonParameters(0).String())
+	assert.Equal(t, "SimonParameters(11)", simon.SimonParameters(11).String())
+	assert.PanicsWithValue(t, "Invalid parameters", func() {
+		simon.KeySize(0)
+	})
+}
+
+func TestInvalidKeyLength(t *testing.T) {
+	params := []simon.SimonParameters{
+		simon.Simon3264,
+		simon.Simon4872,
+		simon.Simon4896,
+		simon.Simon6496,
+		simon.Simon64128,
+		simon.Simon9696,
+		simon.Simon96144,
+		simon.Simon128128,
+		simon.Simon128192,
+		simon.Simon128256,
+	}
+	for _, param := range params {
+		key := testKey(param)
+		ctx, err := simon.New(key[1:], param)
+		assert.Nil(t, ctx)
+		assert.ErrorIs(t, cipher.ErrInvalidKeyLength, err)
+	}
+}
+
+func TestInvalidParam(t *testing.T) {
+	assert.PanicsWithValue(t, "Invalid parameters", func() {
+		simon.New(nil, -1)
+	})
+	assert.PanicsWithValue(t, "Invalid parameters", func() {
+		simon.New(nil, 0)
+	})
+	assert.PanicsWithValue(t, "Invalid parameters", func() {
+		simon.New(nil, simon.Simon128256+1)
+	})
+}
@@ -0,0 +1,118 @@
+package impl_test
+
+import (
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/cipher/speck/impl"
+	"github.com/stretchr/testify/assert"
+)
+
+func BenchmarkKeyschedule6496(b *testing.B) {
+	key := make([]byte, impl.KeySize6496)
+	_, err := io.ReadFull(rand.Reader, key)
+	assert.Nil(b, err)
+
+	_, err = impl.New64(key)
+	assert.Nil(b, err)
+
+	b.ResetTimer()
+	for range b.N {
+		impl.New64(key)
+	}
+}
+
+func BenchmarkKeyschedule64128(b *testing.B) {
+	key := make([]byte, impl.KeySize64128)
+	_, err := io.ReadFull(rand.Reader, key)
+	assert.Nil(b, err)
+
+	_, err = impl.New64(key)
+	assert.Nil(b, err)
+
+	b.ResetTimer()
+	for range b.N {
+		impl.New64(key)
+	}
+}
+
+func BenchmarkEncrypt6496(b *testing.B) {
+	key := make([]byte, impl.KeySize6496)
+	_, err := io.ReadFull(rand.Reader, key)
+	assert.Nil(b, err)
+
+	ctx, err := impl.New64(key)
+	assert.Nil(b, err)
+	b.SetBytes(int64(ctx.BlockSize()))
+
+	ciphertext := make([]byte, ctx.BlockSize())
+	plaintext := make([]byte, ctx.BlockSize())
+	_, err = io.ReadFull(rand.Reader, plaintext)
+	assert.Nil(b, err)
+
+	b.ResetTimer()
+	for range b.N {
+		ctx.Encrypt(ciphertext, plaintext)
+	}
+}
+
+func BenchmarkDecrypt6496(b *testing.B) {
+	key := make([]byte, impl.KeySize6496)
+	_, err := io.ReadFull(rand.Reader, key)
+	assert.Nil(b, err)
+
+	ctx, err := impl.New64(key)
+	assert.Nil(b, err)
+	b.SetBytes(int64(ctx.BlockSize()))
+
+	plaintext := make([]byte, ctx.BlockSize())
+	ciphertext := make([]byte, ctx.BlockSize())
+	_, err = io.ReadFull(rand.Reader, ciphertext)
+	assert.Nil(b, err)
+
+	b.ResetTimer()
+	for range b.N {
+		ctx.Decrypt(plaintext, ciphertext)
+	}
+}
+
+func BenchmarkEncrypt64128(b *testing.B) {
+	key := make([]byte, impl.KeySize64128)
+	_, err := io.ReadFull(rand.Reader, key)
+	assert.Nil(b, err)
+
+	ctx, err := impl.New64(key)
+	assert.Nil(b, err)
+	b.SetBytes(int64(ctx.BlockSize()))
+
+	ciphertext := make([]byte, ctx.BlockSize())
+	plaintext := make([]byte, ctx.BlockSize())
+	_, err = io.ReadFull(rand.Reader, plaintext)
+	assert.Nil(b, err)
+
+	b.ResetTimer()
+	for range b.N {
+		ctx.Encrypt(ciphertext, plaintext)
+	}
+}
+
+func BenchmarkDecrypt64128(b *testing.B) {
+	key := make([]byte, impl.KeySize64128)
+	_, err := io.ReadFull(rand.Reader, key)
+	assert.Nil(b, err)
+
+	ctx, err := impl.New64(key)
+	assert.Nil(b, err)
+	b.SetBytes(int64(ctx.BlockSize()))
+
+	plaintext := make([]byte, ctx.BlockSize())
+	ciphertext := make([]byte, ctx.BlockSize())
+	_, err = io.ReadFull(rand.Reader, ciphertext)
+	assert.Nil(b, err)
+
+	b.ResetTimer()
+	for range b.N {
+		ctx.Decrypt(plaintext, ciphertext)
+	}
+}
@@ -0,0 +1,126 @@
+package impl_test
+
+import (
+	"reflect"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/cipher"
+	"git.omicron.one/playground/cryptography/cipher/speck/impl"
+	. "git.omicron.one/playground/cryptography/util"
+	"github.com/stretchr/testify/assert"
+)
+
+type roundsBlock interface {
+	cipher.Block
+	EncryptRounds(dst, src []byte, r1, r2 int)
+	DecryptRounds(dst, src []byte, r1, r2 int)
+}
+
+// testRounds checks that a reduced-round instance with the full number of
+// rounds behaves the same as the regular instance and that partial
+// encryption and decryption compose to the full operations.
+func testRounds(t *testing.T, full, reduced roundsBlock, rounds int) {
+	t.Helper()
+
+	assert.Equal(t, full.Algorithm(), reduced.Algorithm())
+
+	bs := full.BlockSize()
+	plaintext := make([]byte, bs)
+	for i := range plaintext {
+		plaintext[i] = byte(i * 17)
+	}
+	expected := make([]byte, bs)
+	full.Encrypt(expected, plaintext)
+
+	buffer := make([]byte, bs)
+	reduced.Encrypt(buffer, plaintext)
+	assert.Equal(t, expected, buffer)
+
+	for r := range rounds + 1 {
+		full.EncryptRounds(buffer, plaintext, 0, r)
+		full.EncryptRounds(buffer, buffer, r, rounds)
+		assert.Equal(t, expected, buffer)
+
+		full.DecryptRounds(buffer, expected, r, rounds)
+		full.DecryptRounds(buffer, buffer, 0, r)
+		assert.Equal(t, plaintext, buffer)
+	}
+
+	// An empty range is the identity
+	full.EncryptRounds(buffer, plaintext, 3, 3)
+	assert.Equal(t, plaintext, buffer)
+
+	assert.PanicsWithValue(t, "Invalid round range", func() {
+		full.EncryptRounds(buffer, buffer, -1, 1)
+	})
+	assert.PanicsWithValue(t, "Invalid round range", func() {
+		full.EncryptRounds(buffer, buffer, 2, 1)
+	})
+	assert.PanicsWithValue(t, "Invalid round range", func() {
+		full.DecryptRounds(buffer, buffer, 0, rounds+1)
+	})
+}
+
+// roundKeys returns the round keys of a Speck instance, whose type depends on
+// the word size
+func roundKeys(b roundsBlock) reflect.Value {
+	return reflect.ValueOf(b).Elem().FieldByName("Keys")
+}
+
+func newBlock[B roundsBlock](f func(key []byte) (B, error)) func(key []byte) (roundsBlock, error) {
+	return func(key []byte) (roundsBlock, error) {
+		return f(key)
+	}
+}
+
+func newRoundsBlock[B roundsBlock](f func(key []byte, rounds int) (B, error)) func(key []byte, rounds int) (roundsBlock, error) {
+	return func(key []byte, rounds int) (roundsBlock, error) {
+		return f(key, rounds)
+	}
+}
+
+func TestRounds(t *testing.T) {
+	tests := []struct {
+		name      string
+		key       string
+		new       func(key []byte) (roundsBlock, error)
+		newRounds func(key []byte, rounds int) (roundsBlock, error)
+		rounds    int
+	}{
+		{"Speck32/64", "1918111009080100", newBlock(impl.New32), newRoundsBlock(impl.New32Rounds), impl.Rounds3264},
+		{"Speck48/72", "1211100a0908020100", newBlock(impl.New48), newRoundsBlock(impl.New48Rounds), impl.Rounds4872},
+		{"Speck48/96", "1a19181211100a0908020100", newBlock(impl.New48), newRoundsBlock(impl.New48Rounds), impl.Rounds4896},
+		{"Speck64/96", "131211100b0a090803020100", newBlock(impl.New64), newRoundsBlock(impl.New64Rounds), impl.Rounds6496},
+		{"Speck64/128", "1b1a1918131211100b0a090803020100", newBlock(impl.New64), newRoundsBlock(impl.New64Rounds), impl.Rounds64128},
+		{"Speck96/96", "0d0c0b0a0908050403020100", newBlock(impl.New96), newRoundsBlock(impl.New96Rounds), impl.Rounds9696},
+		{"Speck96/144", "1514131211100d0c0b0a0908050403020100", newBlock(impl.New96), newRoundsBlock(impl.New96Rounds), impl.Rounds96144},
+		{"Speck128/128", "0f0e0d0c0b0a09080706050403020100", newBlock(impl.New128), newRoundsBlock(impl.New128Rounds), impl.Rounds128128},
+		{"Speck128/192", "17161514131211100f0e0d0c0b0a09080706050403020100", newBlock(impl.New128), newRoundsBlock(impl.New128Rounds), impl.Rounds128192},
+		{"Speck128/256", "1f1e1d1c1b1a191817161514131211100f0e0d0c0b0a09080706050403020100", newBlock(impl.New128), newRoundsBlock(impl.New128Rounds), impl.Rounds128256},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			key := DeHex(test.key)
+
+			full, err := test.new(key)
+			assert.Nil(t, err)
+			reduced, err := test.newRounds(key, test.rounds)
+			assert.Nil(t, err)
+			testRounds(t, full, reduced, test.rounds)
+
+			short, err := test.newRounds(key, 7)
+			assert.Nil(t, err)
+			assert.Equal(t, 7, roundKeys(short).Len())
+			assert.Equal(t, roundKeys(full).Slice(0, 7).Interface(), roundKeys(short).Interface())
+
+			long, err := test.newRounds(key, test.rounds+5)
+			assert.Nil(t, err)
+			assert.Equal(t, roundKeys(full).Interface(), roundKeys(long).Slice(0, test.rounds).Interface())
+
+			_, err = test.newRounds(key, 0)
+			assert.ErrorIs(t, err, cipher.ErrInvalidRounds)
+			_, err = test.newRounds(key[1:], 7)
+			assert.ErrorIs(t, err, cipher.ErrInvalidKeyLength)
+		})
+	}
+}
@@ -22,12 +22,32 @@ const (
 
 type Speck128 struct {
 	Keys []uint64
+
+	keyWords int
 }
 
 func New128(key []byte) (*Speck128, error) {
+	switch len(key) {
+	case KeySize128128:
+		return New128Rounds(key, Rounds128128)
+	case KeySize128192:
+		return New128Rounds(key, Rounds128192)
+	case KeySize128256:
+		return New128Rounds(key, Rounds128256)
+	}
+	return nil, cipher.ErrInvalidKeyLength
+}
+
+// New128Rounds creates a Speck128 instance that uses the given number of
+// rounds instead of the number of rounds defined for the key size. The key
+// schedule is expanded to exactly that many round keys.
+func New128Rounds(key []byte, rounds int) (*Speck128, error) {
+	if rounds < 1 {
+		return nil, cipher.ErrInvalidRounds
+	}
+
 	var k [4]uint64
 	var m int
-	var rounds int
 
 	// Note that the layout of the k array is slightly different from the spec.
 	// Compared to the spec it is laid out like this:
@@ -41,18 +61,15 @@ func New128(key []byte) (*Speck128, error) {
 
 	switch len(key) {
 	case KeySize128128:
-		rounds = Rounds128128
 		m = 1
 		k[0] = binary.BigEndian.Uint64(key[:8])
 		k[1] = binary.BigEndian.Uint64(key[8:])
 	case KeySize128192:
-		rounds = Rounds128192
 		m = 2
 		k[0] = binary.BigEndian.Uint64(key[8:])
 		k[1] = binary.BigEndian.Uint64(key[:8])
 		k[2] = binary.BigEndian.Uint64(key[16:24])
 	case KeySize128256:
-		rounds = Rounds128256
 		m = 3
 		k[0] = binary.BigEndian.Uint64(key[16:24])
 		k[1] = binary.BigEndian.Uint64(key[8:])
@@ -63,7 +80,8 @@ func New128(key []byte) (*Speck128, error) {
 	}
 
 	ctx := &Speck128{
-		Keys: make([]uint64, rounds),
+		Keys:     make([]uint64, rounds),
+		keyWords: m,
 	}
 
 	ctx.Keys[0] = k[m]
@@ -87,13 +105,23 @@ func InverseRound128(k, x1, x2 uint64) (uint64, uint64) {
 }
 
 func (ctx *Speck128) Encrypt(dst, src []byte) {
+	ctx.EncryptRounds(dst, src, 0, len(ctx.Keys))
+}
+
+// EncryptRounds applies rounds r1 up to but not including r2 to src and
+// writes the result to dst. Panics if the blocks are not sized correctly or if
+// the round range is not within 0 <= r1 <= r2 <= len(Keys).
+func (ctx *Speck128) EncryptRounds(dst, src []byte, r1, r2 int) {
 	if len(dst) != BlockSize128 || len(src) != BlockSize128 {
 		panic("Incorrect blocksize, expected 128 bits")
 	}
+	if r1 < 0 || r1 > r2 || r2 > len(ctx.Keys) {
+		panic("Invalid round range")
+	}
 
 	x1 := binary.BigEndian.Uint64(src[:8])
 	x2 := binary.BigEndian.Uint64(src[8:])
-	for _, k := range ctx.Keys {
+	for _, k := range ctx.Keys[r1:r2] {
 		x1, x2 = Round128(k, x1, x2)
 	}
 	binary.BigEndian.PutUint64(dst[:8], x1)
@@ -101,12 +129,22 @@ func (ctx *Speck128) Encrypt(dst, src []byte) {
 }
 
 func (ctx *Speck128) Decrypt(dst, src []byte) {
+	ctx.DecryptRounds(dst, src, 0, len(ctx.Keys))
+}
+
+// DecryptRounds inverts rounds r1 up to but not including r2, undoing
+// EncryptRounds with the same range. Panics if the blocks are not sized
+// correctly or if the round range is not within 0 <= r1 <= r2 <= len(Keys).
+func (ctx *Speck128) DecryptRounds(dst, src []byte, r1, r2 int) {
 	if len(dst) != BlockSize128 || len(src) != BlockSize128 {
 		panic("Incorrect blocksize, expected 128 bits")
 	}
+	if r1 < 0 || r1 > r2 || r2 > len(ctx.Keys) {
+		panic("Invalid round range")
+	}
 	x1 := binary.BigEndian.Uint64(src[:8])
 	x2 := binary.BigEndian.Uint64(src[8:])
-	for i := len(ctx.Keys) - 1; i >= 0; i-- {
+	for i := r2 - 1; i >= r1; i-- {
 		x1, x2 = InverseRound128(ctx.Keys[i], x1, x2)
 	}
 	binary.BigEndian.PutUint64(dst[:8], x1)
@@ -118,12 +156,12 @@ func (ctx *Speck128) BlockSize() int {
 }
 
 func (ctx *Speck128) Algorithm() string {
-	switch len(ctx.Keys) {
-	case Rounds128128:
+	switch ctx.keyWords {
+	case 1:
 		return "Speck128/128"
-	case Rounds128192:
+	case 2:
 		return "Speck128/192"
-	case Rounds128256:
+	case 3:
 		return "Speck128/256"
 	}
 	panic("unreachable")
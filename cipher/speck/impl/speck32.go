@@ -0,0 +1,123 @@
+package impl
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"git.omicron.one/playground/cryptography/cipher"
+)
+
+const (
+	BlockSize32 = 32 / 8
+	KeySize3264 = 64 / 8
+	Rounds3264  = 22
+)
+
+type Speck32 struct {
+	Keys []uint16
+}
+
+func New32(key []byte) (*Speck32, error) {
+	return New32Rounds(key, Rounds3264)
+}
+
+// New32Rounds creates a Speck32 instance that uses the given number of
+// rounds instead of the number of rounds defined for the key size. The key
+// schedule is expanded to exactly that many round keys.
+func New32Rounds(key []byte, rounds int) (*Speck32, error) {
+	if rounds < 1 {
+		return nil, cipher.ErrInvalidRounds
+	}
+
+	const m = 3
+	var k [4]uint16
+
+	if len(key) != KeySize3264 {
+		return nil, cipher.ErrInvalidKeyLength
+	}
+
+	// The k array uses the same layout as in New128:
+	// k[0] = l_0, k[1] = l_1, k[2] = l_2, k[3] = k_0
+	k[0] = binary.BigEndian.Uint16(key[4:6])
+	k[1] = binary.BigEndian.Uint16(key[2:4])
+	k[2] = binary.BigEndian.Uint16(key[:2])
+	k[3] = binary.BigEndian.Uint16(key[6:])
+
+	ctx := &Speck32{
+		Keys: make([]uint16, rounds),
+	}
+
+	ctx.Keys[0] = k[m]
+	for i := 0; i < rounds-1; i++ {
+		k[i%m], k[m] = Round32(uint16(i), k[i%m], k[m])
+		ctx.Keys[i+1] = k[m]
+	}
+	return ctx, nil
+}
+
+func Round32(k, x1, x2 uint16) (uint16, uint16) {
+	x1 = (bits.RotateLeft16(x1, 16-7) + x2) ^ k
+	x2 = bits.RotateLeft16(x2, 2) ^ x1
+	return x1, x2
+}
+
+func InverseRound32(k, x1, x2 uint16) (uint16, uint16) {
+	x2 = bits.RotateLeft16(x2^x1, 16-2)
+	x1 = bits.RotateLeft16((x1^k)-x2, 7)
+	return x1, x2
+}
+
+func (ctx *Speck32) Encrypt(dst, src []byte) {
+	ctx.EncryptRounds(dst, src, 0, len(ctx.Keys))
+}
+
+// EncryptRounds applies rounds r1 up to but not including r2 to src and
+// writes the result to dst. Panics if the blocks are not sized correctly or if
+// the round range is not within 0 <= r1 <= r2 <= len(Keys).
+func (ctx *Speck32) EncryptRounds(dst, src []byte, r1, r2 int) {
+	if len(dst) != BlockSize32 || len(src) != BlockSize32 {
+		panic("Incorrect blocksize, expected 32 bits")
+	}
+	if r1 < 0 || r1 > r2 || r2 > len(ctx.Keys) {
+		panic("Invalid round range")
+	}
+
+	x1 := binary.BigEndian.Uint16(src[:2])
+	x2 := binary.BigEndian.Uint16(src[2:])
+	for _, k := range ctx.Keys[r1:r2] {
+		x1, x2 = Round32(k, x1, x2)
+	}
+	binary.BigEndian.PutUint16(dst[:2], x1)
+	binary.BigEndian.PutUint16(dst[2:], x2)
+}
+
+func (ctx *Speck32) Decrypt(dst, src []byte) {
+	ctx.DecryptRounds(dst, src, 0, len(ctx.Keys))
+}
+
+// DecryptRounds inverts rounds r1 up to but not including r2, undoing
+// EncryptRounds with the same range. Panics if the blocks are not sized
+// correctly or if the round range is not within 0 <= r1 <= r2 <= len(Keys).
+func (ctx *Speck32) DecryptRounds(dst, src []byte, r1, r2 int) {
+	if len(dst) != BlockSize32 || len(src) != BlockSize32 {
+		panic("Incorrect blocksize, expected 32 bits")
+	}
+	if r1 < 0 || r1 > r2 || r2 > len(ctx.Keys) {
+		panic("Invalid round range")
+	}
+	x1 := binary.BigEndian.Uint16(src[:2])
+	x2 := binary.BigEndian.Uint16(src[2:])
+	for i := r2 - 1; i >= r1; i-- {
+		x1, x2 = InverseRound32(ctx.Keys[i], x1, x2)
+	}
+	binary.BigEndian.PutUint16(dst[:2], x1)
+	binary.BigEndian.PutUint16(dst[2:], x2)
+}
+
+func (ctx *Speck32) BlockSize() int {
+	return BlockSize32
+}
+
+func (ctx *Speck32) Algorithm() string {
+	return "Speck32/64"
+}
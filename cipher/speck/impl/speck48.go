@@ -0,0 +1,166 @@
+package impl
+
+import (
+	"git.omicron.one/playground/cryptography/cipher"
+)
+
+const (
+	BlockSize48 = 48 / 8
+	KeySize4872 = 72 / 8
+	KeySize4896 = 96 / 8
+	Rounds4872  = 22
+	Rounds4896  = 23
+
+	mask24 = 1<<24 - 1
+)
+
+// Speck48 stores its 24-bit words in the lower bits of uint32 values. The upper
+// 8 bits of every word are always zero.
+type Speck48 struct {
+	Keys []uint32
+
+	keyWords int
+}
+
+func New48(key []byte) (*Speck48, error) {
+	switch len(key) {
+	case KeySize4872:
+		return New48Rounds(key, Rounds4872)
+	case KeySize4896:
+		return New48Rounds(key, Rounds4896)
+	}
+	return nil, cipher.ErrInvalidKeyLength
+}
+
+// New48Rounds creates a Speck48 instance that uses the given number of
+// rounds instead of the number of rounds defined for the key size. The key
+// schedule is expanded to exactly that many round keys.
+func New48Rounds(key []byte, rounds int) (*Speck48, error) {
+	if rounds < 1 {
+		return nil, cipher.ErrInvalidRounds
+	}
+
+	var k [4]uint32
+	var m int
+
+	// The k array uses the same layout as in New128
+	switch len(key) {
+	case KeySize4872:
+		m = 2
+		k[0] = uint24(key[3:6])
+		k[1] = uint24(key[:3])
+		k[2] = uint24(key[6:9])
+	case KeySize4896:
+		m = 3
+		k[0] = uint24(key[6:9])
+		k[1] = uint24(key[3:6])
+		k[2] = uint24(key[:3])
+		k[3] = uint24(key[9:12])
+	default:
+		return nil, cipher.ErrInvalidKeyLength
+	}
+
+	ctx := &Speck48{
+		Keys:     make([]uint32, rounds),
+		keyWords: m,
+	}
+
+	ctx.Keys[0] = k[m]
+	for i := 0; i < rounds-1; i++ {
+		k[i%m], k[m] = Round48(uint32(i), k[i%m], k[m])
+		ctx.Keys[i+1] = k[m]
+	}
+	return ctx, nil
+}
+
+// uint24 decodes a big endian 24-bit word
+func uint24(b []byte) uint32 {
+	_ = b[2] // bounds check hint to compiler
+	return uint32(b[2]) | uint32(b[1])<<8 | uint32(b[0])<<16
+}
+
+// putUint24 encodes a 24-bit word in big endian byte order
+func putUint24(b []byte, v uint32) {
+	_ = b[2] // early bounds check
+	b[0] = byte(v >> 16)
+	b[1] = byte(v >> 8)
+	b[2] = byte(v)
+}
+
+// rotateLeft24 rotates a 24-bit word left by 0 <= k < 24 bits
+func rotateLeft24(x uint32, k int) uint32 {
+	return (x<<k | x>>(24-k)) & mask24
+}
+
+func Round48(k, x1, x2 uint32) (uint32, uint32) {
+	x1 = ((rotateLeft24(x1, 24-8) + x2) & mask24) ^ k
+	x2 = rotateLeft24(x2, 3) ^ x1
+	return x1, x2
+}
+
+func InverseRound48(k, x1, x2 uint32) (uint32, uint32) {
+	x2 = rotateLeft24(x2^x1, 24-3)
+	x1 = rotateLeft24(((x1^k)-x2)&mask24, 8)
+	return x1, x2
+}
+
+func (ctx *Speck48) Encrypt(dst, src []byte) {
+	ctx.EncryptRounds(dst, src, 0, len(ctx.Keys))
+}
+
+// EncryptRounds applies rounds r1 up to but not including r2 to src and
+// writes the result to dst. Panics if the blocks are not sized correctly or if
+// the round range is not within 0 <= r1 <= r2 <= len(Keys).
+func (ctx *Speck48) EncryptRounds(dst, src []byte, r1, r2 int) {
+	if len(dst) != BlockSize48 || len(src) != BlockSize48 {
+		panic("Incorrect blocksize, expected 48 bits")
+	}
+	if r1 < 0 || r1 > r2 || r2 > len(ctx.Keys) {
+		panic("Invalid round range")
+	}
+
+	x1 := uint24(src[:3])
+	x2 := uint24(src[3:])
+	for _, k := range ctx.Keys[r1:r2] {
+		x1, x2 = Round48(k, x1, x2)
+	}
+	putUint24(dst[:3], x1)
+	putUint24(dst[3:], x2)
+}
+
+func (ctx *Speck48) Decrypt(dst, src []byte) {
+	ctx.DecryptRounds(dst, src, 0, len(ctx.Keys))
+}
+
+// DecryptRounds inverts rounds r1 up to but not including r2, undoing
+// EncryptRounds with the same range. Panics if the blocks are not sized
+// correctly or if the round range is not within 0 <= r1 <= r2 <= len(Keys).
+func (ctx *Speck48) DecryptRounds(dst, src []byte, r1, r2 int) {
+	if len(dst) != BlockSize48 || len(src) != BlockSize48 {
+		panic("Incorrect blocksize, expected 48 bits")
+	}
+	if r1 < 0 || r1 > r2 || r2 > len(ctx.Keys) {
+		panic("Invalid round range")
+	}
+	x1 := uint24(src[:3])
+	x2 := uint24(src[3:])
+	for i := r2 - 1; i >= r1; i-- {
+		x1, x2 = InverseRound48(ctx.Keys[i], x1, x2)
+	}
+	putUint24(dst[:3], x1)
+	putUint24(dst[3:], x2)
+}
+
+func (ctx *Speck48) BlockSize() int {
+	return BlockSize48
+}
+
+func (ctx *Speck48) Algorithm() string {
+	switch ctx.keyWords {
+	case 2:
+		return "Speck48/72"
+	case 3:
+		return "Speck48/96"
+	}
+	panic("unreachable")
+}
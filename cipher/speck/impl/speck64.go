@@ -0,0 +1,146 @@
+package impl
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"git.omicron.one/playground/cryptography/cipher"
+)
+
+const (
+	BlockSize64  = 64 / 8
+	KeySize6496  = 96 / 8
+	KeySize64128 = 128 / 8
+	Rounds6496   = 26
+	Rounds64128  = 27
+)
+
+type Speck64 struct {
+	Keys []uint32
+
+	keyWords int
+}
+
+func New64(key []byte) (*Speck64, error) {
+	switch len(key) {
+	case KeySize6496:
+		return New64Rounds(key, Rounds6496)
+	case KeySize64128:
+		return New64Rounds(key, Rounds64128)
+	}
+	return nil, cipher.ErrInvalidKeyLength
+}
+
+// New64Rounds creates a Speck64 instance that uses the given number of
+// rounds instead of the number of rounds defined for the key size. The key
+// schedule is expanded to exactly that many round keys.
+func New64Rounds(key []byte, rounds int) (*Speck64, error) {
+	if rounds < 1 {
+		return nil, cipher.ErrInvalidRounds
+	}
+
+	var k [4]uint32
+	var m int
+
+	// The k array uses the same layout as in New128
+	switch len(key) {
+	case KeySize6496:
+		m = 2
+		k[0] = binary.BigEndian.Uint32(key[4:8])
+		k[1] = binary.BigEndian.Uint32(key[:4])
+		k[2] = binary.BigEndian.Uint32(key[8:12])
+	case KeySize64128:
+		m = 3
+		k[0] = binary.BigEndian.Uint32(key[8:12])
+		k[1] = binary.BigEndian.Uint32(key[4:8])
+		k[2] = binary.BigEndian.Uint32(key[:4])
+		k[3] = binary.BigEndian.Uint32(key[12:])
+	default:
+		return nil, cipher.ErrInvalidKeyLength
+	}
+
+	ctx := &Speck64{
+		Keys:     make([]uint32, rounds),
+		keyWords: m,
+	}
+
+	ctx.Keys[0] = k[m]
+	for i := 0; i < rounds-1; i++ {
+		k[i%m], k[m] = Round64(uint32(i), k[i%m], k[m])
+		ctx.Keys[i+1] = k[m]
+	}
+	return ctx, nil
+}
+
+func Round64(k, x1, x2 uint32) (uint32, uint32) {
+	x1 = (bits.RotateLeft32(x1, 32-8) + x2) ^ k
+	x2 = bits.RotateLeft32(x2, 3) ^ x1
+	return x1, x2
+}
+
+func InverseRound64(k, x1, x2 uint32) (uint32, uint32) {
+	x2 = bits.RotateLeft32(x2^x1, 32-3)
+	x1 = bits.RotateLeft32((x1^k)-x2, 8)
+	return x1, x2
+}
+
+func (ctx *Speck64) Encrypt(dst, src []byte) {
+	ctx.EncryptRounds(dst, src, 0, len(ctx.Keys))
+}
+
+// EncryptRounds applies rounds r1 up to but not including r2 to src and
+// writes the result to dst. Panics if the blocks are not sized correctly or if
+// the round range is not within 0 <= r1 <= r2 <= len(Keys).
+func (ctx *Speck64) EncryptRounds(dst, src []byte, r1, r2 int) {
+	if len(dst) != BlockSize64 || len(src) != BlockSize64 {
+		panic("Incorrect blocksize, expected 64 bits")
+	}
+	if r1 < 0 || r1 > r2 || r2 > len(ctx.Keys) {
+		panic("Invalid round range")
+	}
+
+	x1 := binary.BigEndian.Uint32(src[:4])
+	x2 := binary.BigEndian.Uint32(src[4:])
+	for _, k := range ctx.Keys[r1:r2] {
+		x1, x2 = Round64(k, x1, x2)
+	}
+	binary.BigEndian.PutUint32(dst[:4], x1)
+	binary.BigEndian.PutUint32(dst[4:], x2)
+}
+
+func (ctx *Speck64) Decrypt(dst, src []byte) {
+	ctx.DecryptRounds(dst, src, 0, len(ctx.Keys))
+}
+
+// DecryptRounds inverts rounds r1 up to but not including r2, undoing
+// EncryptRounds with the same range. Panics if the blocks are not sized
+// correctly or if the round range is not within 0 <= r1 <= r2 <= len(Keys).
+func (ctx *Speck64) DecryptRounds(dst, src []byte, r1, r2 int) {
+	if len(dst) != BlockSize64 || len(src) != BlockSize64 {
+		panic("Incorrect blocksize, expected 64 bits")
+	}
+	if r1 < 0 || r1 > r2 || r2 > len(ctx.Keys) {
+		panic("Invalid round range")
+	}
+	x1 := binary.BigEndian.Uint32(src[:4])
+	x2 := binary.BigEndian.Uint32(src[4:])
+	for i := r2 - 1; i >= r1; i-- {
+		x1, x2 = InverseRound64(ctx.Keys[i], x1, x2)
+	}
+	binary.BigEndian.PutUint32(dst[:4], x1)
+	binary.BigEndian.PutUint32(dst[4:], x2)
+}
+
+func (ctx *Speck64) BlockSize() int {
+	return BlockSize64
+}
+
+func (ctx *Speck64) Algorithm() string {
+	switch ctx.keyWords {
+	case 2:
+		return "Speck64/96"
+	case 3:
+		return "Speck64/128"
+	}
+	panic("unreachable")
+}
@@ -0,0 +1,168 @@
+package impl
+
+import (
+	"git.omicron.one/playground/cryptography/cipher"
+)
+
+const (
+	BlockSize96  = 96 / 8
+	KeySize9696  = 96 / 8
+	KeySize96144 = 144 / 8
+	Rounds9696   = 28
+	Rounds96144  = 29
+
+	mask48 = 1<<48 - 1
+)
+
+// Speck96 stores its 48-bit words in the lower bits of uint64 values. The
+// upper 16 bits of every word are always zero.
+type Speck96 struct {
+	Keys []uint64
+
+	keyWords int
+}
+
+func New96(key []byte) (*Speck96, error) {
+	switch len(key) {
+	case KeySize9696:
+		return New96Rounds(key, Rounds9696)
+	case KeySize96144:
+		return New96Rounds(key, Rounds96144)
+	}
+	return nil, cipher.ErrInvalidKeyLength
+}
+
+// New96Rounds creates a Speck96 instance that uses the given number of
+// rounds instead of the number of rounds defined for the key size. The key
+// schedule is expanded to exactly that many round keys.
+func New96Rounds(key []byte, rounds int) (*Speck96, error) {
+	if rounds < 1 {
+		return nil, cipher.ErrInvalidRounds
+	}
+
+	var k [4]uint64
+	var m int
+
+	// The k array uses the same layout as in New128
+	switch len(key) {
+	case KeySize9696:
+		m = 1
+		k[0] = uint48(key[:6])
+		k[1] = uint48(key[6:12])
+	case KeySize96144:
+		m = 2
+		k[0] = uint48(key[6:12])
+		k[1] = uint48(key[:6])
+		k[2] = uint48(key[12:18])
+	default:
+		return nil, cipher.ErrInvalidKeyLength
+	}
+
+	ctx := &Speck96{
+		Keys:     make([]uint64, rounds),
+		keyWords: m,
+	}
+
+	ctx.Keys[0] = k[m]
+	for i := 0; i < rounds-1; i++ {
+		k[i%m], k[m] = Round96(uint64(i), k[i%m], k[m])
+		ctx.Keys[i+1] = k[m]
+	}
+	return ctx, nil
+}
+
+// uint48 decodes a big endian 48-bit word
+func uint48(b []byte) uint64 {
+	_ = b[5] // bounds check hint to compiler
+	return uint64(b[5]) | uint64(b[4])<<8 | uint64(b[3])<<16 |
+		uint64(b[2])<<24 | uint64(b[1])<<32 | uint64(b[0])<<40
+}
+
+// putUint48 encodes a 48-bit word in big endian byte order
+func putUint48(b []byte, v uint64) {
+	_ = b[5] // early bounds check
+	b[0] = byte(v >> 40)
+	b[1] = byte(v >> 32)
+	b[2] = byte(v >> 24)
+	b[3] = byte(v >> 16)
+	b[4] = byte(v >> 8)
+	b[5] = byte(v)
+}
+
+// rotateLeft48 rotates a 48-bit word left by 0 <= k < 48 bits
+func rotateLeft48(x uint64, k int) uint64 {
+	return (x<<k | x>>(48-k)) & mask48
+}
+
+func Round96(k, x1, x2 uint64) (uint64, uint64) {
+	x1 = ((rotateLeft48(x1, 48-8) + x2) & mask48) ^ k
+	x2 = rotateLeft48(x2, 3) ^ x1
+	return x1, x2
+}
+
+func InverseRound96(k, x1, x2 uint64) (uint64, uint64) {
+	x2 = rotateLeft48(x2^x1, 48-3)
+	x1 = rotateLeft48(((x1^k)-x2)&mask48, 8)
+	return x1, x2
+}
+
+func (ctx *Speck96) Encrypt(dst, src []byte) {
+	ctx.EncryptRounds(dst, src, 0, len(ctx.Keys))
+}
+
+// EncryptRounds applies rounds r1 up to but not including r2 to src and
+// writes the result to dst. Panics if the blocks are not sized correctly or if
+// the round range is not within 0 <= r1 <= r2 <= len(Keys).
+func (ctx *Speck96) EncryptRounds(dst, src []byte, r1, r2 int) {
+	if len(dst) != BlockSize96 || len(src) != BlockSize96 {
+		panic("Incorrect blocksize, expected 96 bits")
+	}
+	if r1 < 0 || r1 > r2 || r2 > len(ctx.Keys) {
+		panic("Invalid round range")
+	}
+
+	x1 := uint48(src[:6])
+	x2 := uint48(src[6:])
+	for _, k := range ctx.Keys[r1:r2] {
+		x1, x2 = Round96(k, x1, x2)
+	}
+	putUint48(dst[:6], x1)
+	putUint48(dst[6:], x2)
+}
+
+func (ctx *Speck96) Decrypt(dst, src []byte) {
+	ctx.DecryptRounds(dst, src, 0, len(ctx.Keys))
+}
+
+// DecryptRounds inverts rounds r1 up to but not including r2, undoing
+// EncryptRounds with the same range. Panics if the blocks are not sized
+// correctly or if the round range is not within 0 <= r1 <= r2 <= len(Keys).
+func (ctx *Speck96) DecryptRounds(dst, src []byte, r1, r2 int) {
+	if len(dst) != BlockSize96 || len(src) != BlockSize96 {
+		panic("Incorrect blocksize, expected 96 bits")
+	}
+	if r1 < 0 || r1 > r2 || r2 > len(ctx.Keys) {
+		panic("Invalid round range")
+	}
+	x1 := uint48(src[:6])
+	x2 := uint48(src[6:])
+	for i := r2 - 1; i >= r1; i-- {
+		x1, x2 = InverseRound96(ctx.Keys[i], x1, x2)
+	}
+	putUint48(dst[:6], x1)
+	putUint48(dst[6:], x2)
+}
+
+func (ctx *Speck96) BlockSize() int {
+	return BlockSize96
+}
+
+func (ctx *Speck96) Algorithm() string {
+	switch ctx.keyWords {
+	case 1:
+		return "Speck96/96"
+	case 2:
+		return "Speck96/144"
+	}
+	panic("unreachable")
+}
@@ -3,12 +3,16 @@
 package speck
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"git.omicron.one/playground/cryptography/cipher"
 	"git.omicron.one/playground/cryptography/cipher/speck/impl"
 )
 
+var ErrUnknownParameters = errors.New("Unknown parameters")
+
 type SpeckParameters int
 
 const (
@@ -38,6 +42,50 @@ var keySizes = []int{
 	32, // Speck128256
 }
 
+var names = []string{
+	"", // unused
+	"Speck32/64",
+	"Speck48/72",
+	"Speck48/96",
+	"Speck64/96",
+	"Speck64/128",
+	"Speck96/96",
+	"Speck96/144",
+	"Speck128/128",
+	"Speck128/192",
+	"Speck128/256",
+}
+
+// String returns the name of the parameter set as used by Algorithm, for
+// example "Speck128/256".
+func (param SpeckParameters) String() string {
+	if param <= 0 || int(param) >= len(names) {
+		return fmt.Sprintf("SpeckParameters(%d)", int(param))
+	}
+	return names[param]
+}
+
+// ParseParameters returns the parameter set with the given name, for example
+// "Speck128/256". The comparison is case insensitive.
+// Returns ErrUnknownParameters if no parameter set has the given name.
+func ParseParameters(name string) (SpeckParameters, error) {
+	for i := 1; i < len(names); i++ {
+		if strings.EqualFold(name, names[i]) {
+			return SpeckParameters(i), nil
+		}
+	}
+	return 0, ErrUnknownParameters
+}
+
+// KeySize returns the key size in bytes for the given parameters.
+// Panics if the parameters are invalid.
+func KeySize(param SpeckParameters) int {
+	if param <= 0 || int(param) >= len(keySizes) {
+		panic("Invalid parameters")
+	}
+	return keySizes[param]
+}
+
 // New creates a new speck block cipher context.
 // Returns the created block cipher or an error.
 func New(key []byte, param SpeckParameters) (cipher.Block, error) {
@@ -50,13 +98,13 @@ func New(key []byte, param SpeckParameters) (cipher.Block, error) {
 	}
 	switch param {
 	case Speck3264:
-		return nil, fmt.Errorf("Not implemented")
+		return impl.New32(key)
 	case Speck4872, Speck4896:
-		return nil, fmt.Errorf("Not implemented")
+		return impl.New48(key)
 	case Speck6496, Speck64128:
-		return nil, fmt.Errorf("Not implemented")
+		return impl.New64(key)
 	case Speck9696, Speck96144:
-		return nil, fmt.Errorf("Not implemented")
+		return impl.New96(key)
 	case Speck128128, Speck128192, Speck128256:
 		return impl.New128(key)
 	}
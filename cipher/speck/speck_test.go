@@ -29,7 +29,7 @@ func testKey(param speck.SpeckParameters) []byte {
 }
 
 func TestNew(t *testing.T) {
-	notImplemented := []speck.SpeckParameters{
+	params := []speck.SpeckParameters{
 		speck.Speck3264,
 		speck.Speck4872,
 		speck.Speck4896,
@@ -37,21 +37,12 @@ func TestNew(t *testing.T) {
 		speck.Speck64128,
 		speck.Speck9696,
 		speck.Speck96144,
-	}
-	implemented := []speck.SpeckParameters{
 		speck.Speck128128,
 		speck.Speck128192,
 		speck.Speck128256,
 	}
 
-	for _, param := range notImplemented {
-		key := testKey(param)
-		ctx, err := speck.New(key, param)
-		assert.Nil(t, ctx)
-		assert.ErrorContains(t, err, "Not implemented")
-	}
-
-	for _, param := range implemented {
+	for _, param := range params {
 		key := testKey(param)
 		ctx, err := speck.New(key, param)
 		assert.Nil(t, err)
@@ -91,3 +82,45 @@ func TestInvalidParam(t *testing.T) {
 		speck.New(nil, speck.Speck128256+1)
 	})
 }
+
+func TestParameterNames(t *testing.T) {
+	params := []speck.SpeckParameters{
+		speck.Speck3264,
+		speck.Speck4872,
+		speck.Speck4896,
+		speck.Speck6496,
+		speck.Speck64128,
+		speck.Speck9696,
+		speck.Speck96144,
+		speck.Speck128128,
+		speck.Speck128192,
+		speck.Speck128256,
+	}
+	for _, param := range params {
+		key := testKey(param)
+		assert.Equal(t, len(key), speck.KeySize(param))
+
+		ctx, err := speck.New(key, param)
+		assert.Nil(t, err)
+		assert.Equal(t, ctx.Algorithm(), param.String())
+
+		parsed, err := speck.ParseParameters(param.String())
+		assert.Nil(t, err)
+		assert.Equal(t, param, parsed)
+	}
+
+	parsed, err := speck.ParseParameters("speck64/128")
+	assert.Nil(t, err)
+	assert.Equal(t, speck.SpeckParameters(speck.Speck64128), parsed)
+
+	_, err = speck.ParseParameters("Speck128")
+	assert.ErrorIs(t, err, speck.ErrUnknownParameters)
+	_, err = speck.ParseParameters("")
+	assert.ErrorIs(t, err, speck.ErrUnknownParameters)
+
+	assert.Equal(t, "SpeckParameters(0)", speck.SpeckParameters(0).String())
+	assert.Equal(t, "SpeckParameters(11)", speck.SpeckParameters(11).String())
+	assert.PanicsWithValue(t, "Invalid parameters", func() {
+		speck.KeySize(0)
+	})
+}
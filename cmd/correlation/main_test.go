@@ -0,0 +1,40 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/analysis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := run([]string{
+		"-rounds", "1",
+		"-in", "100,1",
+		"-out", "1,0",
+		"-keys", "4",
+		"-samples", "64",
+	}, &stdout, &stderr)
+	assert.Nil(t, err)
+
+	var result analysis.CorrelationResult
+	assert.Nil(t, json.Unmarshal(stdout.Bytes(), &result))
+	assert.Equal(t, 1, result.Rounds)
+	assert.Equal(t, analysis.Mask{X: 0x100, Y: 1}, result.InputMask)
+	assert.Equal(t, analysis.Mask{X: 1}, result.OutputMask)
+	assert.Equal(t, 4, result.Keys)
+	assert.Equal(t, 64, result.Samples)
+	assert.Equal(t, 1.0, result.Potential)
+	assert.Equal(t, 4, result.KeyCorrelations.Rows())
+}
+
+func TestRunErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.ErrorIs(t, run([]string{"-in", "1", "-out", "1,0"}, &stdout, &stderr), errInvalidMask)
+	assert.ErrorIs(t, run([]string{"-in", "1,0", "-out", "x,0"}, &stdout, &stderr), errInvalidMask)
+	assert.ErrorIs(t, run([]string{"-in", "1,0", "-out", "1,10000000000000000"}, &stdout, &stderr), errInvalidMask)
+	assert.ErrorIs(t, run([]string{"-in", "1,0", "-out", "1,0", "-rounds", "0"}, &stdout, &stderr), analysis.ErrInvalidExperiment)
+}
@@ -0,0 +1,223 @@
+// randomness applies the statistical tests of the randomness package to
+// counter mode keystreams of the registered block ciphers.
+//
+// Usage:
+//
+//	randomness [flags]
+//
+// The cipher is selected by name with -cipher, for example Speck128/256, or
+// all registered ciphers are tested with -cipher all. Keys and IVs are derived
+// from -seed, so runs are reproducible. Every cipher yields several p-values,
+// so -alpha is the significance level of the whole run: with the Bonferroni
+// correction every p-value is compared with -alpha divided by the number of
+// p-values of all tested ciphers. Sound ciphers then fail in at most a fraction
+// -alpha of the runs. A cipher passes if none of its p-values is below the
+// corrected level. The exit status is non-zero if any cipher fails.
+//
+// Example:
+//
+//	randomness -cipher Simon64/128 -bits 1000000 -json
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"strings"
+
+	"git.omicron.one/playground/cryptography/cipher"
+	"git.omicron.one/playground/cryptography/cipher/modes"
+	"git.omicron.one/playground/cryptography/cipher/simon"
+	"git.omicron.one/playground/cryptography/cipher/speck"
+	"git.omicron.one/playground/cryptography/randomness"
+)
+
+var (
+	errUnknownCipher = errors.New("Unknown cipher, use -list to show the registered ciphers")
+	errFailed        = errors.New("Randomness tests failed")
+)
+
+// registeredCipher is a block cipher that can be tested
+type registeredCipher struct {
+	name    string
+	keySize int
+	new     func(key []byte) (cipher.Block, error)
+}
+
+// registry returns all registered ciphers
+func registry() []registeredCipher {
+	var ciphers []registeredCipher
+	for p := speck.Speck3264; p <= speck.Speck128256; p++ {
+		param := speck.SpeckParameters(p)
+		ciphers = append(ciphers, registeredCipher{
+			name:    param.String(),
+			keySize: speck.KeySize(param),
+			new: func(key []byte) (cipher.Block, error) {
+				return speck.New(key, param)
+			},
+		})
+	}
+	for p := simon.Simon3264; p <= simon.Simon128256; p++ {
+		param := simon.SimonParameters(p)
+		ciphers = append(ciphers, registeredCipher{
+			name:    param.String(),
+			keySize: simon.KeySize(param),
+			new: func(key []byte) (cipher.Block, error) {
+				return simon.New(key, param)
+			},
+		})
+	}
+	return ciphers
+}
+
+// report holds the results for a single cipher. Alpha is the corrected
+// significance level the p-values were compared with.
+type report struct {
+	Cipher  string              `json:"cipher"`
+	Passed  bool                `json:"passed"`
+	Alpha   float64             `json:"alpha"`
+	Results []randomness.Result `json:"results"`
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdout, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "randomness:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("randomness", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	name := fs.String("cipher", "all", "cipher to test, for example Speck128/256, or all")
+	list := fs.Bool("list", false, "list the registered ciphers")
+	length := fs.Int("bits", 1<<20, "number of keystream bits per cipher")
+	seed := fs.Uint64("seed", 1, "seed for the keys and IVs")
+	alpha := fs.Float64("alpha", 0.01, "significance level of all tests together")
+	asJSON := fs.Bool("json", false, "write the results as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ciphers := registry()
+	if *list {
+		for _, c := range ciphers {
+			fmt.Fprintln(stdout, c.name)
+		}
+		return nil
+	}
+	if !strings.EqualFold(*name, "all") {
+		found := false
+		for _, c := range ciphers {
+			if strings.EqualFold(*name, c.name) {
+				ciphers = []registeredCipher{c}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return errUnknownCipher
+		}
+	}
+	if *length < randomness.MinSuiteLength {
+		return fmt.Errorf("Invalid number of bits %d, need at least %d", *length, randomness.MinSuiteLength)
+	}
+	if *alpha <= 0 || *alpha > 1 {
+		return fmt.Errorf("Invalid significance level %g", *alpha)
+	}
+
+	reports, err := testCiphers(ciphers, *length, *seed, *alpha)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(reports); err != nil {
+			return err
+		}
+	} else {
+		writeReports(stdout, reports)
+	}
+
+	for _, r := range reports {
+		if !r.Passed {
+			return errFailed
+		}
+	}
+	return nil
+}
+
+// testCiphers tests all ciphers and decides whether they pass at the
+// significance level alpha for all tests together, using the Bonferroni
+// correction
+func testCiphers(ciphers []registeredCipher, length int, seed uint64, alpha float64) ([]report, error) {
+	reports := make([]report, 0, len(ciphers))
+	tests := 0
+	for _, c := range ciphers {
+		results, err := testCipher(c, length, seed)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", c.name, err)
+		}
+		for _, result := range results {
+			tests += len(result.PValues)
+		}
+		reports = append(reports, report{Cipher: c.name, Results: results})
+	}
+
+	for i := range reports {
+		r := &reports[i]
+		r.Alpha = alpha / float64(tests)
+		r.Passed = true
+		for _, result := range r.Results {
+			r.Passed = r.Passed && result.Passed(r.Alpha)
+		}
+	}
+	return reports, nil
+}
+
+// testCipher runs the test suite on length bits of counter mode keystream
+func testCipher(c registeredCipher, length int, seed uint64) ([]randomness.Result, error) {
+	rng := rand.New(rand.NewPCG(seed, 0))
+	key := make([]byte, c.keySize)
+	for i := range key {
+		key[i] = byte(rng.Uint32())
+	}
+	b, err := c.new(key)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, b.BlockSize())
+	for i := range iv {
+		iv[i] = byte(rng.Uint32())
+	}
+
+	keystream := make([]byte, (length+7)/8)
+	modes.NewCTR(b, iv).XORKeyStream(keystream, keystream)
+	return randomness.Suite(randomness.NewSequence(keystream)[:length])
+}
+
+func writeReports(w io.Writer, reports []report) {
+	for _, r := range reports {
+		for _, result := range r.Results {
+			status := "PASS"
+			if !result.Passed(r.Alpha) {
+				status = "FAIL"
+			}
+			fmt.Fprintf(w, "%-13s %-19s %s", r.Cipher, result.Name, status)
+			for _, p := range result.PValues {
+				fmt.Fprintf(w, "  %.6f", p)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/cipher"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	ciphers := registry()
+	assert.Len(t, ciphers, 20)
+	for _, c := range ciphers {
+		b, err := c.new(make([]byte, c.keySize))
+		assert.Nil(t, err)
+		assert.Equal(t, c.name, b.Algorithm())
+	}
+}
+
+func TestRun(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"-cipher", "speck64/128", "-bits", "65536"}, &stdout, &stderr)
+	assert.Nil(t, err)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	assert.Len(t, lines, 7)
+	for _, line := range lines {
+		assert.True(t, strings.HasPrefix(line, "Speck64/128"), line)
+		assert.Contains(t, line, "PASS")
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"-bits", "4096", "-json"}, &stdout, &stderr)
+	assert.Nil(t, err)
+
+	var reports []report
+	assert.Nil(t, json.Unmarshal(stdout.Bytes(), &reports))
+	assert.Len(t, reports, 20)
+	for _, r := range reports {
+		assert.True(t, r.Passed, r.Cipher)
+		assert.Len(t, r.Results, 7)
+	}
+}
+
+func TestRunSeeds(t *testing.T) {
+	// Sound ciphers must pass regardless of the seed, since the significance
+	// level applies to all tests together
+	for seed := range 5 {
+		var stdout, stderr bytes.Buffer
+		err := run([]string{"-bits", "65536", "-seed", strconv.Itoa(seed + 1)}, &stdout, &stderr)
+		assert.Nil(t, err, "seed %d", seed+1)
+		assert.NotContains(t, stdout.String(), "FAIL")
+	}
+}
+
+func TestTestCiphers(t *testing.T) {
+	// A broken cipher that outputs its input fails the suite
+	identity := registeredCipher{
+		name:    "Identity",
+		keySize: 0,
+		new: func(key []byte) (cipher.Block, error) {
+			return identityBlock{}, nil
+		},
+	}
+	ciphers := append(registry()[:1], identity)
+	reports, err := testCiphers(ciphers, 1<<14, 1, 0.01)
+	assert.Nil(t, err)
+	assert.Len(t, reports, 2)
+	assert.True(t, reports[0].Passed)
+	assert.False(t, reports[1].Passed)
+
+	// The significance level is divided over the 9 p-values of each cipher
+	for _, r := range reports {
+		assert.InDelta(t, 0.01/18, r.Alpha, 1e-15)
+	}
+
+	var out strings.Builder
+	writeReports(&out, reports)
+	assert.Contains(t, out.String(), "Identity      Frequency           FAIL")
+}
+
+type identityBlock struct{}
+
+func (identityBlock) Encrypt(dst, src []byte) { copy(dst, src) }
+func (identityBlock) Decrypt(dst, src []byte) { copy(dst, src) }
+func (identityBlock) BlockSize() int          { return 8 }
+func (identityBlock) Algorithm() string       { return "Identity" }
+
+func TestRunErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.ErrorIs(t, run([]string{"-cipher", "aes"}, &stdout, &stderr), errUnknownCipher)
+	assert.ErrorContains(t, run([]string{"-bits", "100"}, &stdout, &stderr), "Invalid number of bits")
+	assert.ErrorContains(t, run([]string{"-alpha", "0"}, &stdout, &stderr), "Invalid significance level")
+	assert.ErrorContains(t, run([]string{"-alpha", "1.5"}, &stdout, &stderr), "Invalid significance level")
+
+	stdout.Reset()
+	assert.Nil(t, run([]string{"-list"}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), "Simon128/256\n")
+	assert.Contains(t, stdout.String(), "Speck32/64\n")
+}
@@ -0,0 +1,284 @@
+// speck encrypts and decrypts files or standard input with the Speck block
+// cipher.
+//
+// Usage:
+//
+//	speck [flags]
+//
+// The key is given either as a hexadecimal string with -key or as a file
+// containing the raw key bytes with -keyfile. The ECB and CBC modes use PKCS #7
+// padding, the CFB, OFB and CTR modes don't change the length of the data and
+// GCM appends an authentication tag. All modes except ECB require an IV, which
+// is used as the nonce for GCM.
+//
+// Example:
+//
+//	speck -param Speck128/256 -keyfile secret.key -mode ctr -iv 000102030405060708090a0b0c0d0e0f -in plain.txt -out cipher.bin
+package main
+
+import (
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"git.omicron.one/playground/cryptography/cipher"
+	"git.omicron.one/playground/cryptography/cipher/modes"
+	"git.omicron.one/playground/cryptography/cipher/speck"
+	"git.omicron.one/playground/cryptography/padding"
+)
+
+// chunkSize is the number of bytes read from the input at a time
+const chunkSize = 64 * 1024
+
+var (
+	errNoKey       = errors.New("Exactly one of -key and -keyfile must be given")
+	errNoIV        = errors.New("Mode requires an IV, use -iv")
+	errUnknownMode = errors.New("Unknown mode, expected one of ecb, cbc, cfb, ofb, ctr or gcm")
+)
+
+type options struct {
+	param   string
+	key     string
+	keyFile string
+	mode    string
+	iv      string
+	decrypt bool
+	in      string
+	out     string
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "speck:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the command line arguments and processes the input. stdin and
+// stdout are used unless an input or output file is given.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var opts options
+	fs := flag.NewFlagSet("speck", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.param, "param", "Speck128/256", "parameter set, e.g. Speck64/128")
+	fs.StringVar(&opts.key, "key", "", "key as a hexadecimal string")
+	fs.StringVar(&opts.keyFile, "keyfile", "", "file containing the raw key bytes")
+	fs.StringVar(&opts.mode, "mode", "ctr", "mode of operation: ecb, cbc, cfb, ofb, ctr or gcm")
+	fs.StringVar(&opts.iv, "iv", "", "IV or nonce as a hexadecimal string")
+	fs.BoolVar(&opts.decrypt, "d", false, "decrypt instead of encrypt")
+	fs.StringVar(&opts.in, "in", "", "input file (default stdin)")
+	fs.StringVar(&opts.out, "out", "", "output file (default stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("Unexpected argument %q", fs.Arg(0))
+	}
+
+	b, err := newBlock(&opts)
+	if err != nil {
+		return err
+	}
+
+	var iv []byte
+	if opts.iv != "" {
+		if iv, err = hex.DecodeString(opts.iv); err != nil {
+			return fmt.Errorf("Invalid IV: %w", err)
+		}
+	}
+
+	in := stdin
+	if opts.in != "" {
+		f, err := os.Open(opts.in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	mode := strings.ToLower(opts.mode)
+	if opts.out == "" {
+		return process(in, stdout, b, mode, iv, opts.decrypt)
+	}
+
+	f, err := os.Create(opts.out)
+	if err != nil {
+		return err
+	}
+	err = process(in, f, b, mode, iv, opts.decrypt)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// Don't leave a partial output file behind
+		os.Remove(opts.out)
+	}
+	return err
+}
+
+// newBlock creates the block cipher from the parameter and key options
+func newBlock(opts *options) (cipher.Block, error) {
+	param, err := speck.ParseParameters(opts.param)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", err, opts.param)
+	}
+
+	var key []byte
+	switch {
+	case opts.key != "" && opts.keyFile == "":
+		if key, err = hex.DecodeString(opts.key); err != nil {
+			return nil, fmt.Errorf("Invalid key: %w", err)
+		}
+	case opts.key == "" && opts.keyFile != "":
+		if key, err = os.ReadFile(opts.keyFile); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, errNoKey
+	}
+
+	b, err := speck.New(key, param)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s requires %d bytes, got %d", err, param, speck.KeySize(param), len(key))
+	}
+	return b, nil
+}
+
+// process encrypts or decrypts the input with the given mode and writes the
+// result to the output
+func process(in io.Reader, out io.Writer, b cipher.Block, mode string, iv []byte, decrypt bool) error {
+	if mode != "ecb" && iv == nil {
+		return errNoIV
+	}
+	if mode != "ecb" && mode != "gcm" && len(iv) != b.BlockSize() {
+		return fmt.Errorf("Invalid IV length, %s requires %d bytes", b.Algorithm(), b.BlockSize())
+	}
+
+	switch mode {
+	case "ecb":
+		if decrypt {
+			return cryptBlocks(in, out, modes.NewECBDecrypter(b), true)
+		}
+		return cryptBlocks(in, out, modes.NewECBEncrypter(b), false)
+	case "cbc":
+		if decrypt {
+			return cryptBlocks(in, out, modes.NewCBCDecrypter(b, iv), true)
+		}
+		return cryptBlocks(in, out, modes.NewCBCEncrypter(b, iv), false)
+	case "cfb":
+		if decrypt {
+			return cryptStream(in, out, modes.NewCFBDecrypter(b, iv))
+		}
+		return cryptStream(in, out, modes.NewCFBEncrypter(b, iv))
+	case "ofb":
+		return cryptStream(in, out, modes.NewOFB(b, iv))
+	case "ctr":
+		return cryptStream(in, out, modes.NewCTR(b, iv))
+	case "gcm":
+		return cryptAEAD(in, out, b, iv, decrypt)
+	}
+	return errUnknownMode
+}
+
+// cryptStream XORs the input with the key stream in chunks
+func cryptStream(in io.Reader, out io.Writer, stream cipher.Stream) error {
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := in.Read(buf)
+		if n > 0 {
+			stream.XORKeyStream(buf[:n], buf[:n])
+			if _, werr := out.Write(buf[:n]); werr != nil {
+				return werr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// cryptBlocks processes the input in whole blocks. The last block is held back
+// until the end of the input, so it can be padded or unpadded.
+func cryptBlocks(in io.Reader, out io.Writer, mode cipher.BlockMode, decrypt bool) error {
+	bs := mode.BlockSize()
+	pad := padding.NewPKCS7(bs)
+	buf := make([]byte, 0, chunkSize+bs)
+	chunk := make([]byte, chunkSize)
+
+	for {
+		n, err := in.Read(chunk)
+		buf = append(buf, chunk[:n]...)
+
+		keep := len(buf) % bs
+		if decrypt && keep == 0 {
+			keep = bs
+		}
+		if end := len(buf) - keep; end > 0 {
+			mode.CryptBlocks(buf[:end], buf[:end])
+			if _, werr := out.Write(buf[:end]); werr != nil {
+				return werr
+			}
+			buf = buf[:copy(buf, buf[end:])]
+		}
+
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if !decrypt {
+		padded := pad.Pad(buf)
+		mode.CryptBlocks(padded, padded)
+		_, err := out.Write(padded)
+		return err
+	}
+
+	if len(buf) != bs {
+		return padding.ErrInvalidLength
+	}
+	mode.CryptBlocks(buf, buf)
+	unpadded, err := pad.Unpad(buf)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(unpadded)
+	return err
+}
+
+// cryptAEAD seals or opens the whole input at once, the plaintext can only be
+// released after the tag has been verified
+func cryptAEAD(in io.Reader, out io.Writer, b cipher.Block, nonce []byte, decrypt bool) error {
+	aead, err := cipher.NewGCMWithParameters(b, len(nonce), 16)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+
+	if decrypt {
+		if data, err = aead.Open(data[:0], nonce, data, nil); err != nil {
+			return err
+		}
+	} else {
+		data = aead.Seal(data[:0], nonce, data, nil)
+	}
+	_, err = out.Write(data)
+	return err
+}
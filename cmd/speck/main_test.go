@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/cipher"
+	"git.omicron.one/playground/cryptography/padding"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testKey = "1f1e1d1c1b1a191817161514131211100f0e0d0c0b0a09080706050403020100"
+	testIV  = "000102030405060708090a0b0c0d0e0f"
+)
+
+func testRun(t *testing.T, input []byte, args ...string) ([]byte, error) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	err := run(args, bytes.NewReader(input), &stdout, &stderr)
+	return stdout.Bytes(), err
+}
+
+func TestRoundTrip(t *testing.T) {
+	// Cover inputs around the chunk size to exercise the buffering
+	lengths := []int{0, 1, 15, 16, 17, 100, chunkSize - 1, chunkSize, chunkSize + 1, 3*chunkSize + 5}
+	for _, mode := range []string{"ecb", "cbc", "cfb", "ofb", "ctr", "gcm"} {
+		for _, n := range lengths {
+			plaintext := make([]byte, n)
+			for i := range plaintext {
+				plaintext[i] = byte(i * 7)
+			}
+
+			args := []string{"-key", testKey, "-mode", mode, "-iv", testIV}
+			ciphertext, err := testRun(t, plaintext, args...)
+			assert.Nil(t, err, mode)
+			switch mode {
+			case "ecb", "cbc":
+				assert.Equal(t, (n/16+1)*16, len(ciphertext), mode)
+			case "gcm":
+				assert.Equal(t, n+16, len(ciphertext), mode)
+			default:
+				assert.Equal(t, n, len(ciphertext), mode)
+			}
+
+			decrypted, err := testRun(t, ciphertext, append(args, "-d")...)
+			assert.Nil(t, err, mode)
+			assert.Equal(t, plaintext, append([]byte{}, decrypted...), mode)
+		}
+	}
+}
+
+func TestParameters(t *testing.T) {
+	keys := map[string]string{
+		"Speck32/64":   "1918111009080100",
+		"speck48/72":   "1211100a0908020100",
+		"Speck64/128":  "1b1a1918131211100b0a090803020100",
+		"Speck96/144":  "1514131211100d0c0b0a0908050403020100",
+		"Speck128/128": "0f0e0d0c0b0a09080706050403020100",
+	}
+	for param, key := range keys {
+		plaintext := []byte("attack at dawn")
+		ciphertext, err := testRun(t, plaintext, "-param", param, "-key", key, "-mode", "ecb")
+		assert.Nil(t, err, param)
+
+		decrypted, err := testRun(t, ciphertext, "-param", param, "-key", key, "-mode", "ecb", "-d")
+		assert.Nil(t, err, param)
+		assert.Equal(t, plaintext, decrypted, param)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "key")
+	plainFile := filepath.Join(dir, "plain")
+	cipherFile := filepath.Join(dir, "cipher")
+	decryptedFile := filepath.Join(dir, "decrypted")
+
+	assert.Nil(t, os.WriteFile(keyFile, bytes.Repeat([]byte{0x42}, 16), 0o600))
+	assert.Nil(t, os.WriteFile(plainFile, []byte("hello world"), 0o600))
+
+	_, err := testRun(t, nil, "-param", "Speck128/128", "-keyfile", keyFile,
+		"-mode", "cbc", "-iv", testIV, "-in", plainFile, "-out", cipherFile)
+	assert.Nil(t, err)
+	_, err = testRun(t, nil, "-param", "Speck128/128", "-keyfile", keyFile,
+		"-mode", "cbc", "-iv", testIV, "-in", cipherFile, "-out", decryptedFile, "-d")
+	assert.Nil(t, err)
+
+	decrypted, err := os.ReadFile(decryptedFile)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("hello world"), decrypted)
+
+	// Failed decryption doesn't leave an output file behind
+	assert.Nil(t, os.WriteFile(cipherFile, make([]byte, 32), 0o600))
+	_, err = testRun(t, nil, "-param", "Speck128/128", "-keyfile", keyFile,
+		"-mode", "gcm", "-iv", testIV, "-in", cipherFile, "-out", decryptedFile, "-d")
+	assert.ErrorIs(t, err, cipher.ErrOpen)
+	assert.NoFileExists(t, decryptedFile)
+}
+
+func TestErrors(t *testing.T) {
+	_, err := testRun(t, nil, "-mode", "ctr", "-iv", testIV)
+	assert.ErrorIs(t, err, errNoKey)
+
+	_, err = testRun(t, nil, "-key", testKey, "-keyfile", "key", "-iv", testIV)
+	assert.ErrorIs(t, err, errNoKey)
+
+	_, err = testRun(t, nil, "-key", testKey[2:])
+	assert.ErrorIs(t, err, cipher.ErrInvalidKeyLength)
+
+	_, err = testRun(t, nil, "-key", "xyz")
+	assert.ErrorContains(t, err, "Invalid key")
+
+	_, err = testRun(t, nil, "-key", testKey, "-param", "Speck256/256")
+	assert.ErrorContains(t, err, "Unknown parameters")
+
+	_, err = testRun(t, nil, "-key", testKey, "-mode", "cbc")
+	assert.ErrorIs(t, err, errNoIV)
+
+	_, err = testRun(t, nil, "-key", testKey, "-mode", "cbc", "-iv", "0001")
+	assert.ErrorContains(t, err, "Invalid IV length")
+
+	_, err = testRun(t, nil, "-key", testKey, "-mode", "xts", "-iv", testIV)
+	assert.ErrorIs(t, err, errUnknownMode)
+
+	_, err = testRun(t, make([]byte, 15), "-key", testKey, "-mode", "cbc", "-iv", testIV, "-d")
+	assert.ErrorIs(t, err, padding.ErrInvalidLength)
+
+	_, err = testRun(t, make([]byte, 32), "-key", testKey, "-mode", "gcm", "-iv", testIV, "-d")
+	assert.ErrorIs(t, err, cipher.ErrOpen)
+
+	_, err = testRun(t, nil, "-key", testKey, "extra")
+	assert.ErrorContains(t, err, "Unexpected argument")
+}
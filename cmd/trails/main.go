@@ -0,0 +1,124 @@
+// trails searches for optimal differential or linear trails through
+// Speck-like ARX ciphers.
+//
+// Usage:
+//
+//	trails [flags]
+//
+// The round function is selected with -cipher, or given explicitly with
+// -word, -alpha and -beta. For every number of rounds up to -rounds the best
+// trail is printed with its weight, the negated base 2 logarithm of its
+// probability. With -linear linear trails are searched instead and the weight
+// is the negated base 2 logarithm of the absolute correlation. Only small word
+// sizes and round numbers are feasible.
+//
+// Example:
+//
+//	trails -cipher speck32 -rounds 6
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"git.omicron.one/playground/cryptography/analysis"
+)
+
+var errUnknownCipher = errors.New("Unknown cipher, expected one of speck32, speck48, speck64, speck96 or speck128")
+
+var ciphers = map[string]analysis.ARXParameters{
+	"speck32":  analysis.Speck32ARX,
+	"speck48":  analysis.Speck48ARX,
+	"speck64":  analysis.Speck64ARX,
+	"speck96":  analysis.Speck96ARX,
+	"speck128": analysis.Speck128ARX,
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdout, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "trails:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("trails", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	name := fs.String("cipher", "speck32", "cipher: speck32, speck48, speck64, speck96 or speck128")
+	word := fs.Int("word", 0, "word size in bits, overrides the cipher")
+	alpha := fs.Int("alpha", 0, "right rotation of the first word, used with -word")
+	beta := fs.Int("beta", 0, "left rotation of the second word, used with -word")
+	rounds := fs.Int("rounds", 5, "maximum number of rounds")
+	linear := fs.Bool("linear", false, "search for linear instead of differential trails")
+	asJSON := fs.Bool("json", false, "write the trails as JSON")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	p, ok := ciphers[strings.ToLower(*name)]
+	if !ok {
+		return errUnknownCipher
+	}
+	if *word != 0 {
+		p = analysis.ARXParameters{WordSize: *word, Alpha: *alpha, Beta: *beta}
+	}
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if *rounds < 1 {
+		return fmt.Errorf("Invalid number of rounds %d", *rounds)
+	}
+
+	if *linear {
+		trails := analysis.SearchLinearTrails(p, *rounds)
+		if *asJSON {
+			return writeJSON(stdout, trails)
+		}
+		for _, trail := range trails {
+			fmt.Fprintf(stdout, "%d rounds: weight %d (log2 |c| = %g)\n",
+				trail.Rounds(), trail.Weight(), trail.Log2Correlation())
+			for r, m := range trail.Masks {
+				writeStep(stdout, p, r, m.X, m.Y, trail.Weights)
+			}
+		}
+		return nil
+	}
+
+	trails := analysis.SearchDifferentialTrails(p, *rounds)
+	if *asJSON {
+		return writeJSON(stdout, trails)
+	}
+	for _, trail := range trails {
+		fmt.Fprintf(stdout, "%d rounds: weight %d (log2 p = %g)\n",
+			trail.Rounds(), trail.Weight(), trail.Log2Probability())
+		for r, d := range trail.Differences {
+			writeStep(stdout, p, r, d.X, d.Y, trail.Weights)
+		}
+	}
+	return nil
+}
+
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// writeStep writes the words before round r and the weight of that round
+func writeStep(w io.Writer, p analysis.ARXParameters, r int, x, y uint64, weights []int) {
+	digits := (p.WordSize + 3) / 4
+	fmt.Fprintf(w, "  %3d  %0*x %0*x", r, digits, x, digits, y)
+	if r < len(weights) {
+		fmt.Fprintf(w, "  %d", weights[r])
+	}
+	fmt.Fprintln(w)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/analysis"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"-cipher", "speck32", "-rounds", "3"}, &stdout, &stderr)
+	assert.Nil(t, err)
+	assert.Contains(t, stdout.String(), "1 rounds: weight 0")
+	assert.Contains(t, stdout.String(), "3 rounds: weight 3 (log2 p = -3)")
+}
+
+func TestRunJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"-word", "16", "-alpha", "7", "-beta", "2", "-rounds", "4", "-json"}, &stdout, &stderr)
+	assert.Nil(t, err)
+
+	var trails []analysis.DifferentialTrail
+	assert.Nil(t, json.Unmarshal(stdout.Bytes(), &trails))
+	assert.Len(t, trails, 4)
+	assert.Equal(t, 5, trails[3].Weight())
+}
+
+func TestRunLinear(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"-cipher", "speck32", "-rounds", "4", "-linear"}, &stdout, &stderr)
+	assert.Nil(t, err)
+	assert.Contains(t, stdout.String(), "2 rounds: weight 0")
+	assert.Contains(t, stdout.String(), "4 rounds: weight 3 (log2 |c| = -3)")
+
+	stdout.Reset()
+	err = run([]string{"-cipher", "speck32", "-rounds", "4", "-linear", "-json"}, &stdout, &stderr)
+	assert.Nil(t, err)
+
+	var trails []analysis.LinearTrail
+	assert.Nil(t, json.Unmarshal(stdout.Bytes(), &trails))
+	assert.Len(t, trails, 4)
+	assert.Equal(t, 3, trails[3].Weight())
+	assert.Len(t, trails[3].Masks, 5)
+}
+
+func TestRunErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.ErrorIs(t, run([]string{"-cipher", "simon32"}, &stdout, &stderr), errUnknownCipher)
+	assert.ErrorIs(t, run([]string{"-word", "65"}, &stdout, &stderr), analysis.ErrInvalidBits)
+	assert.ErrorIs(t, run([]string{"-word", "16", "-alpha", "16"}, &stdout, &stderr), analysis.ErrInvalidRotation)
+	assert.ErrorContains(t, run([]string{"-rounds", "0"}, &stdout, &stderr), "Invalid number of rounds")
+}
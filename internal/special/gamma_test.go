@@ -0,0 +1,29 @@
+package special_test
+
+import (
+	"math"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/internal/special"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIgamc(t *testing.T) {
+	// Closed forms for a = 1 and a = 1/2
+	for _, x := range []float64{0.01, 0.5, 1, 2.5, 10, 50} {
+		assert.InDelta(t, math.Exp(-x), special.Igamc(1, x), 1e-14)
+		assert.InDelta(t, math.Erfc(math.Sqrt(x)), special.Igamc(0.5, x), 1e-14)
+		assert.InDelta(t, 1, special.Igamc(3.5, x)+special.Igam(3.5, x), 1e-14)
+	}
+
+	// The 5% critical value of the chi-squared distribution with 10 degrees of
+	// freedom
+	assert.InDelta(t, 0.05, special.Igamc(5, 18.307/2), 1e-5)
+
+	assert.Equal(t, 1.0, special.Igamc(1, 0))
+	assert.Equal(t, 1.0, special.Igamc(0, 1))
+	assert.Equal(t, 0.0, special.Igam(1, -1))
+	assert.Equal(t, 0.0, special.Igamc(1, 1e6))
+	assert.Equal(t, 0.0, special.Igamc(1, math.Inf(1)))
+	assert.Equal(t, 1.0, special.Igam(1, math.Inf(1)))
+}
@@ -0,0 +1,124 @@
+// Package mac implements message authentication codes on top of the block
+// ciphers in this module.
+package mac
+
+import (
+	"crypto/subtle"
+	"errors"
+	"hash"
+
+	"git.omicron.one/playground/cryptography/cipher"
+)
+
+var ErrUnsupportedBlockSize = errors.New("Unsupported block size")
+
+// The constants R_b from NIST SP 800-38B used to derive the subkeys for each
+// supported block size
+const (
+	rb64  = 0x1b
+	rb128 = 0x87
+)
+
+type cmac struct {
+	b      cipher.Block
+	k1, k2 []byte
+	x      []byte
+	buf    []byte
+	n      int
+}
+
+// NewCMAC returns a hash.Hash that computes the CMAC (OMAC1) of the written
+// data, as defined in NIST SP 800-38B and RFC 4493, using the given Block. The
+// size of the MAC is the block size of the cipher.
+//
+// Returns ErrUnsupportedBlockSize if the block size isn't 64 or 128 bits.
+func NewCMAC(b cipher.Block) (hash.Hash, error) {
+	var rb byte
+	switch b.BlockSize() {
+	case 64 / 8:
+		rb = rb64
+	case 128 / 8:
+		rb = rb128
+	default:
+		return nil, ErrUnsupportedBlockSize
+	}
+
+	bs := b.BlockSize()
+	h := &cmac{
+		b:   b,
+		k1:  make([]byte, bs),
+		k2:  make([]byte, bs),
+		x:   make([]byte, bs),
+		buf: make([]byte, bs),
+	}
+
+	b.Encrypt(h.k1, h.k1)
+	shift(h.k1, h.k1, rb)
+	shift(h.k2, h.k1, rb)
+
+	return h, nil
+}
+
+// shift sets dst to src multiplied by x in GF(2^n), that is src shifted left
+// by one bit and reduced with rb if the most significant bit was set. The
+// reduction is done without branching on the secret value.
+func shift(dst, src []byte, rb byte) {
+	mask := -(src[0] >> 7)
+	var carry byte
+	for i := len(src) - 1; i >= 0; i-- {
+		b := src[i]
+		dst[i] = b<<1 | carry
+		carry = b >> 7
+	}
+	dst[len(dst)-1] ^= rb & mask
+}
+
+func (h *cmac) Size() int {
+	return h.b.BlockSize()
+}
+
+func (h *cmac) BlockSize() int {
+	return h.b.BlockSize()
+}
+
+func (h *cmac) Reset() {
+	clear(h.x)
+	clear(h.buf)
+	h.n = 0
+}
+
+func (h *cmac) Write(p []byte) (int, error) {
+	written := len(p)
+	bs := len(h.buf)
+
+	for len(p) > 0 {
+		// The last block is processed differently, so only process the
+		// buffered block once it is known that more data follows
+		if h.n == bs {
+			subtle.XORBytes(h.x, h.x, h.buf)
+			h.b.Encrypt(h.x, h.x)
+			h.n = 0
+		}
+		n := copy(h.buf[h.n:], p)
+		h.n += n
+		p = p[n:]
+	}
+	return written, nil
+}
+
+func (h *cmac) Sum(in []byte) []byte {
+	bs := len(h.buf)
+	last := make([]byte, bs)
+	copy(last, h.buf[:h.n])
+
+	if h.n == bs {
+		subtle.XORBytes(last, last, h.k1)
+	} else {
+		last[h.n] = 0x80
+		subtle.XORBytes(last, last, h.k2)
+	}
+
+	subtle.XORBytes(last, last, h.x)
+	h.b.Encrypt(last, last)
+	return append(in, last...)
+}
@@ -0,0 +1,108 @@
+package mac_test
+
+import (
+	"crypto/aes"
+	gocipher "crypto/cipher"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/cipher"
+	"git.omicron.one/playground/cryptography/cipher/speck"
+	"git.omicron.one/playground/cryptography/cipher/speck/impl"
+	"git.omicron.one/playground/cryptography/mac"
+	. "git.omicron.one/playground/cryptography/util"
+	"github.com/stretchr/testify/assert"
+)
+
+// aesBlock adapts the standard library AES implementation to cipher.Block so
+// CMAC can be checked against the RFC 4493 test vectors
+type aesBlock struct {
+	gocipher.Block
+}
+
+func (b aesBlock) Algorithm() string {
+	return "AES"
+}
+
+var message = DeHex("6bc1bee22e409f96e93d7e117393172a" +
+	"ae2d8a571e03ac9c9eb76fac45af8e51" +
+	"30c81c46a35ce411e5fbc1191a0a52ef" +
+	"f69f2445df4f9b17ad2b417be66c3710")
+
+func testCMAC(t *testing.T, b cipher.Block, msg, expected []byte) {
+	t.Helper()
+
+	h, err := mac.NewCMAC(b)
+	assert.Nil(t, err)
+	assert.Equal(t, b.BlockSize(), h.Size())
+	assert.Equal(t, b.BlockSize(), h.BlockSize())
+
+	n, err := h.Write(msg)
+	assert.Nil(t, err)
+	assert.Equal(t, len(msg), n)
+	assert.Equal(t, expected, h.Sum(nil))
+
+	// Sum doesn't change the state
+	assert.Equal(t, expected, h.Sum(nil))
+
+	// Byte by byte writes
+	h.Reset()
+	for i := range msg {
+		h.Write(msg[i : i+1])
+	}
+	assert.Equal(t, expected, h.Sum(nil))
+
+	// Sum appends
+	prefix := []byte{1, 2, 3}
+	assert.Equal(t, append(prefix, expected...), h.Sum(prefix[:3:3]))
+}
+
+func TestCMACRFC4493(t *testing.T) {
+	block, err := aes.NewCipher(DeHex("2b7e151628aed2a6abf7158809cf4f3c"))
+	assert.Nil(t, err)
+	b := aesBlock{block}
+
+	testCMAC(t, b, message[:0], DeHex("bb1d6929e95937287fa37d129b756746"))
+	testCMAC(t, b, message[:16], DeHex("070a16b46b4d4144f79bdd9dd04a287c"))
+	testCMAC(t, b, message[:40], DeHex("dfa66747de9ae63030ca32611497c827"))
+	testCMAC(t, b, message[:64], DeHex("51f0bebf7e3b9d92fc49741779363cfe"))
+}
+
+// The Speck vectors were cross-checked against an independent implementation
+func TestCMACSpeck128(t *testing.T) {
+	b, err := impl.New128(DeHex("0f0e0d0c0b0a09080706050403020100"))
+	assert.Nil(t, err)
+
+	testCMAC(t, b, message[:0], DeHex("2d3911608b973f14341d5ea3e9a5d6c5"))
+	testCMAC(t, b, message[:16], DeHex("b5cb009b78ec69f953c0bfbaa2e7bc1c"))
+	testCMAC(t, b, message[:40], DeHex("0af50087ecc18a9cf4137a46c66e5c78"))
+	testCMAC(t, b, message[:64], DeHex("3098b94182acf6c732e0ec4e164b0a23"))
+}
+
+func TestCMACSpeck64(t *testing.T) {
+	b, err := impl.New64(DeHex("1b1a1918131211100b0a090803020100"))
+	assert.Nil(t, err)
+
+	testCMAC(t, b, message[:0], DeHex("640f874e94768ce4"))
+	testCMAC(t, b, message[:8], DeHex("c7025f037223f060"))
+	testCMAC(t, b, message[:20], DeHex("17b792c9fa7186b1"))
+	testCMAC(t, b, message[:32], DeHex("2665aff78c686f57"))
+}
+
+func TestCMACUnsupportedBlockSize(t *testing.T) {
+	tests := []struct {
+		param   speck.SpeckParameters
+		keySize int
+	}{
+		{speck.Speck3264, impl.KeySize3264},
+		{speck.Speck4872, impl.KeySize4872},
+		{speck.Speck9696, impl.KeySize9696},
+	}
+	for _, test := range tests {
+		b, err := speck.New(make([]byte, test.keySize), test.param)
+		assert.Nil(t, err)
+
+		h, err := mac.NewCMAC(b)
+		assert.ErrorIs(t, err, mac.ErrUnsupportedBlockSize)
+		assert.Nil(t, h)
+	}
+}
@@ -0,0 +1,57 @@
+package matrix
+
+import "sync"
+
+// Accumulator collects values into a matrix from several goroutines without
+// locking on every update. Every goroutine obtains its own shard with Shard
+// and updates it like any other matrix; Merge sums all shards. Since every
+// shard has a single writer no updates get lost, so the totals of integer
+// matrices are exact regardless of the scheduling. For floats the rounding
+// may depend on how the values were divided over the shards.
+//
+// A shard must only be used by one goroutine at a time and all writes to the
+// shards must happen before Merge, for example by waiting for the workers
+// with a sync.WaitGroup.
+type Accumulator[T Number] struct {
+	rows   int
+	cols   int
+	mu     sync.Mutex
+	shards []*Matrix[T]
+}
+
+// NewAccumulator creates an accumulator for matrices with the given number of
+// rows and columns.
+//
+// Panics with ErrInvalidDimensions if rows < 1 or cols < 1.
+func NewAccumulator[T Number](rows, cols int) *Accumulator[T] {
+	if rows < 1 || cols < 1 {
+		panic(ErrInvalidDimensions)
+	}
+	return &Accumulator[T]{
+		rows: rows,
+		cols: cols,
+	}
+}
+
+// Shard returns a new zero matrix that is included in Merge. Shard can be
+// called concurrently, typically once at the start of every worker.
+func (a *Accumulator[T]) Shard() *Matrix[T] {
+	shard := Create[T](a.rows, a.cols)
+	a.mu.Lock()
+	a.shards = append(a.shards, shard)
+	a.mu.Unlock()
+	return shard
+}
+
+// Size returns the number of rows and columns of the accumulated matrices
+func (a *Accumulator[T]) Size() (int, int) {
+	return a.rows, a.cols
+}
+
+// Merge returns a new matrix with the sum of all shards, in the order in
+// which they were created. Returns a zero matrix if there are no shards.
+func (a *Accumulator[T]) Merge() *Matrix[T] {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return Create[T](a.rows, a.cols).Add(a.shards...)
+}
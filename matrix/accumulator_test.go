@@ -0,0 +1,53 @@
+package matrix_test
+
+import (
+	"sync"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccumulator(t *testing.T) {
+	const (
+		workers = 16
+		updates = 1 << 15
+	)
+	a := matrix.NewAccumulator[uint64](4, 8)
+	rows, cols := a.Size()
+	assert.Equal(t, 4, rows)
+	assert.Equal(t, 8, cols)
+	assert.Equal(t, matrix.Create[uint64](4, 8), a.Merge())
+
+	// All workers update the same elements
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			shard := a.Shard()
+			for i := range updates {
+				row, col := i%4, (i/4+w)%8
+				shard.Set(row, col, shard.Get(row, col)+1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	total := a.Merge()
+	assert.Equal(t, uint64(workers*updates), total.Total())
+	expected := matrix.Create[uint64](4, 8).Fill(workers * updates / 32)
+	assert.Equal(t, expected, total)
+
+	// Merge doesn't modify the shards
+	assert.Equal(t, total, a.Merge())
+}
+
+func TestAccumulatorInvalid(t *testing.T) {
+	assert.PanicsWithValue(t, matrix.ErrInvalidDimensions, func() {
+		matrix.NewAccumulator[int](0, 1)
+	})
+	assert.PanicsWithValue(t, matrix.ErrInvalidDimensions, func() {
+		matrix.NewAccumulator[int](1, 0)
+	})
+}
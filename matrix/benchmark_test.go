@@ -0,0 +1,70 @@
+package matrix_test
+
+import (
+	"fmt"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/matrix"
+)
+
+var benchmarkSizes = [][2]int{{16, 16}, {256, 256}, {1024, 1024}, {65536, 4}}
+
+func BenchmarkAdd(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%dx%d", size[0], size[1]), func(b *testing.B) {
+			m := matrix.Create[int](size[0], size[1])
+			other := matrix.Create[int](size[0], size[1]).Fill(1)
+			b.SetBytes(int64(8 * size[0] * size[1]))
+			for range b.N {
+				m.Add(other)
+			}
+		})
+	}
+}
+
+func BenchmarkHadamardMultiply(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%dx%d", size[0], size[1]), func(b *testing.B) {
+			m := matrix.Create[float64](size[0], size[1]).Fill(1)
+			other := matrix.Create[float64](size[0], size[1]).Fill(1)
+			b.SetBytes(int64(8 * size[0] * size[1]))
+			for range b.N {
+				m.HadamardMultiply(other)
+			}
+		})
+	}
+}
+
+func BenchmarkCreate(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%dx%d", size[0], size[1]), func(b *testing.B) {
+			for range b.N {
+				matrix.Create[int](size[0], size[1])
+			}
+		})
+	}
+}
+
+func BenchmarkMarshalJSON(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%dx%d", size[0], size[1]), func(b *testing.B) {
+			m := matrix.Create[int](size[0], size[1]).Fill(12345)
+			b.SetBytes(int64(8 * size[0] * size[1]))
+			for range b.N {
+				m.MarshalJSON()
+			}
+		})
+	}
+}
+
+func BenchmarkMarshalBinary(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		b.Run(fmt.Sprintf("%dx%d", size[0], size[1]), func(b *testing.B) {
+			m := matrix.Create[int](size[0], size[1]).Fill(12345)
+			b.SetBytes(int64(8 * size[0] * size[1]))
+			for range b.N {
+				m.MarshalBinary()
+			}
+		})
+	}
+}
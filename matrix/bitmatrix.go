@@ -0,0 +1,356 @@
+package matrix
+
+import (
+	"errors"
+	"math/bits"
+)
+
+var ErrNoSolution = errors.New("System has no solution")
+
+// BitMatrix represents a matrix over GF(2). Every row is packed into uint64
+// words, with column j stored in bit j%64 of word j/64 of the row. Addition is
+// XOR and multiplication is AND, so the matrix represents a linear map between
+// bit vectors.
+type BitMatrix struct {
+	rows   int
+	cols   int
+	stride int
+	values []uint64
+}
+
+// CreateBitMatrix creates a new bit matrix with the given number of rows and
+// columns. All values of this matrix are set to zero. Returns the new matrix.
+//
+// Panics with ErrInvalidDimensions if rows < 1 or cols < 1.
+func CreateBitMatrix(rows, cols int) *BitMatrix {
+	if rows < 1 || cols < 1 {
+		panic(ErrInvalidDimensions)
+	}
+	stride := (cols + 63) / 64
+	return &BitMatrix{
+		rows:   rows,
+		cols:   cols,
+		stride: stride,
+		values: make([]uint64, rows*stride),
+	}
+}
+
+// CreateBitMatrixFromSlice creates a new bit matrix from a given 2D slice of
+// values. The first index of the slice denotes the rows and the second index
+// denotes the columns. Only the least significant bit of every value is used.
+// Returns the newly created matrix.
+//
+// Panics with ErrInvalidDimensions if any of the lengths are 0.
+// Panics with ErrIncompatibleDataDimensions if not all rows have the same
+// length.
+func CreateBitMatrixFromSlice(values [][]uint8) *BitMatrix {
+	rows := len(values)
+	if rows == 0 {
+		panic(ErrInvalidDimensions)
+	}
+	cols := len(values[0])
+	if cols == 0 {
+		panic(ErrInvalidDimensions)
+	}
+
+	m := CreateBitMatrix(rows, cols)
+	for i := range rows {
+		if len(values[i]) != cols {
+			panic(ErrIncompatibleDataDimensions)
+		}
+		for j, v := range values[i] {
+			m.Set(i, j, v)
+		}
+	}
+	return m
+}
+
+// IdentityBitMatrix creates a new n x n identity bit matrix.
+//
+// Panics with ErrInvalidDimensions if n < 1.
+func IdentityBitMatrix(n int) *BitMatrix {
+	m := CreateBitMatrix(n, n)
+	for i := range n {
+		m.Set(i, i, 1)
+	}
+	return m
+}
+
+// row returns the packed words of row i
+func (m *BitMatrix) row(i int) []uint64 {
+	return m.values[i*m.stride : (i+1)*m.stride]
+}
+
+// swapRows exchanges rows i and j
+func (m *BitMatrix) swapRows(i, j int) {
+	ri, rj := m.row(i), m.row(j)
+	for k := range ri {
+		ri[k], rj[k] = rj[k], ri[k]
+	}
+}
+
+// xorRow adds row src to row dst
+func (m *BitMatrix) xorRow(dst, src int) {
+	rd, rs := m.row(dst), m.row(src)
+	for k := range rd {
+		rd[k] ^= rs[k]
+	}
+}
+
+// Copy creates a deep copy of the matrix and returns the new instance
+func (m *BitMatrix) Copy() *BitMatrix {
+	mCopy := *m
+	mCopy.values = append([]uint64(nil), m.values...)
+	return &mCopy
+}
+
+// Size returns the dimensions of the matrix as (rows, columns)
+func (m *BitMatrix) Size() (int, int) {
+	return m.rows, m.cols
+}
+
+// Rows returns the number of rows in the matrix
+func (m *BitMatrix) Rows() int {
+	return m.rows
+}
+
+// Cols returns the number of columns in the matrix
+func (m *BitMatrix) Cols() int {
+	return m.cols
+}
+
+// Set sets the value of the matrix at the given row and col position to the
+// least significant bit of value
+func (m *BitMatrix) Set(row, col int, value uint8) {
+	if row < 0 || row >= m.rows || col < 0 || col >= m.cols {
+		panic("Index out of range")
+	}
+	word := &m.values[row*m.stride+col/64]
+	*word = *word&^(1<<(col%64)) | uint64(value&1)<<(col%64)
+}
+
+// Get returns the value of the element at the given row and column, 0 or 1
+func (m *BitMatrix) Get(row, col int) uint8 {
+	if row < 0 || row >= m.rows || col < 0 || col >= m.cols {
+		panic("Index out of range")
+	}
+	return uint8(m.values[row*m.stride+col/64] >> (col % 64) & 1)
+}
+
+// Add performs in-place addition, which is XOR over GF(2), of zero or more
+// matrices to this matrix and returns the receiver.
+// Ensures correct behavior even if the matrix itself is passed as one or more
+// arguments.
+// Panics with ErrIncompatibleMatrixDimensions if any of the matrices don't
+// have matching dimensions.
+func (m *BitMatrix) Add(matrices ...*BitMatrix) *BitMatrix {
+	numSelf := 0
+	for _, other := range matrices {
+		if m.rows != other.rows || m.cols != other.cols {
+			panic(ErrIncompatibleMatrixDimensions)
+		}
+		if other == m {
+			numSelf += 1
+		}
+	}
+
+	// Every self reference adds the original values, so they cancel out in
+	// pairs
+	original := m
+	if numSelf > 0 {
+		original = m.Copy()
+	}
+
+	for _, other := range matrices {
+		if other == m {
+			other = original
+		}
+		for k, v := range other.values {
+			m.values[k] ^= v
+		}
+	}
+	return m
+}
+
+// Multiply computes the matrix product m * other over GF(2) and returns it as
+// a new matrix.
+//
+// Panics with ErrIncompatibleMatrixDimensions if the number of columns of m
+// doesn't match the number of rows of other.
+func (m *BitMatrix) Multiply(other *BitMatrix) *BitMatrix {
+	if m.cols != other.rows {
+		panic(ErrIncompatibleMatrixDimensions)
+	}
+
+	out := CreateBitMatrix(m.rows, other.cols)
+	for i := range m.rows {
+		dst := out.row(i)
+		for k, word := range m.row(i) {
+			for ; word != 0; word &= word - 1 {
+				src := other.row(64*k + bits.TrailingZeros64(word))
+				for w := range dst {
+					dst[w] ^= src[w]
+				}
+			}
+		}
+	}
+	return out
+}
+
+// MultiplyVector computes the product m * v of the matrix with a column
+// vector and returns it as a new vector. The vectors are packed like the rows
+// of the matrix.
+//
+// Panics with ErrIncompatibleDataDimensions if v has less than
+// (cols + 63) / 64 words.
+func (m *BitMatrix) MultiplyVector(v []uint64) []uint64 {
+	if len(v) < m.stride {
+		panic(ErrIncompatibleDataDimensions)
+	}
+
+	out := make([]uint64, (m.rows+63)/64)
+	for i := range m.rows {
+		parity := 0
+		for k, word := range m.row(i) {
+			parity ^= bits.OnesCount64(word & v[k])
+		}
+		out[i/64] |= uint64(parity&1) << (i % 64)
+	}
+	return out
+}
+
+// Transpose creates the transpose of the matrix and returns the new instance
+func (m *BitMatrix) Transpose() *BitMatrix {
+	out := CreateBitMatrix(m.cols, m.rows)
+	for i := range m.rows {
+		for k, word := range m.row(i) {
+			for ; word != 0; word &= word - 1 {
+				j := 64*k + bits.TrailingZeros64(word)
+				out.values[j*out.stride+i/64] |= 1 << (i % 64)
+			}
+		}
+	}
+	return out
+}
+
+// reduce transforms m into reduced row echelon form with Gauss-Jordan
+// elimination. Every row operation is also applied to aug, if it isn't nil.
+// Returns the pivot column of every non-zero row.
+func (m *BitMatrix) reduce(aug *BitMatrix) []int {
+	var pivots []int
+	for col := 0; col < m.cols && len(pivots) < m.rows; col++ {
+		r := len(pivots)
+		word, bit := col/64, uint64(1)<<(col%64)
+
+		pivot := -1
+		for i := r; i < m.rows; i++ {
+			if m.values[i*m.stride+word]&bit != 0 {
+				pivot = i
+				break
+			}
+		}
+		if pivot < 0 {
+			continue
+		}
+
+		if pivot != r {
+			m.swapRows(pivot, r)
+			if aug != nil {
+				aug.swapRows(pivot, r)
+			}
+		}
+		for i := range m.rows {
+			if i != r && m.values[i*m.stride+word]&bit != 0 {
+				m.xorRow(i, r)
+				if aug != nil {
+					aug.xorRow(i, r)
+				}
+			}
+		}
+		pivots = append(pivots, col)
+	}
+	return pivots
+}
+
+// Rank returns the rank of the matrix over GF(2)
+func (m *BitMatrix) Rank() int {
+	return len(m.Copy().reduce(nil))
+}
+
+// Inverse computes the inverse of the matrix over GF(2) and returns it as a
+// new matrix.
+// Returns ErrIncompatibleMatrixDimensions if the matrix is not square or
+// ErrSingular if the matrix is not invertible.
+func (m *BitMatrix) Inverse() (*BitMatrix, error) {
+	if m.rows != m.cols {
+		return nil, ErrIncompatibleMatrixDimensions
+	}
+
+	inverse := IdentityBitMatrix(m.rows)
+	if len(m.Copy().reduce(inverse)) != m.rows {
+		return nil, ErrSingular
+	}
+	return inverse, nil
+}
+
+// Solve finds a solution x of m * x = b over GF(2), where b may have multiple
+// columns to solve several systems at once. If the solution is not unique the
+// free variables are set to zero; Kernel describes all other solutions.
+// Returns ErrIncompatibleMatrixDimensions if b doesn't have as many rows as m
+// or ErrNoSolution if the system is inconsistent.
+func (m *BitMatrix) Solve(b *BitMatrix) (*BitMatrix, error) {
+	if b.rows != m.rows {
+		return nil, ErrIncompatibleMatrixDimensions
+	}
+
+	reduced := m.Copy()
+	rhs := b.Copy()
+	pivots := reduced.reduce(rhs)
+
+	// All rows without a pivot are zero, so their right hand side must be too
+	for i := len(pivots); i < m.rows; i++ {
+		for _, word := range rhs.row(i) {
+			if word != 0 {
+				return nil, ErrNoSolution
+			}
+		}
+	}
+
+	x := CreateBitMatrix(m.cols, b.cols)
+	for r, col := range pivots {
+		copy(x.row(col), rhs.row(r))
+	}
+	return x, nil
+}
+
+// Kernel computes a basis of the kernel, the vectors x with m * x = 0, over
+// GF(2). Every row of the returned matrix is a basis vector. Returns nil if
+// the kernel only contains the zero vector.
+func (m *BitMatrix) Kernel() *BitMatrix {
+	reduced := m.Copy()
+	pivots := reduced.reduce(nil)
+	if len(pivots) == m.cols {
+		return nil
+	}
+
+	isPivot := make([]bool, m.cols)
+	for _, col := range pivots {
+		isPivot[col] = true
+	}
+
+	// Every free column gives a basis vector with that variable set to one and
+	// the pivot variables chosen to cancel it
+	kernel := CreateBitMatrix(m.cols-len(pivots), m.cols)
+	k := 0
+	for free := range m.cols {
+		if isPivot[free] {
+			continue
+		}
+		kernel.Set(k, free, 1)
+		for r, col := range pivots {
+			kernel.Set(k, col, reduced.Get(r, free))
+		}
+		k++
+	}
+	return kernel
+}
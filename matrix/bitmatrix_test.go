@@ -0,0 +1,279 @@
+package matrix_test
+
+import (
+	"math/bits"
+	"math/rand/v2"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+func randomBitMatrix(rows, cols int, seed uint64) *matrix.BitMatrix {
+	rng := rand.New(rand.NewPCG(seed, 1))
+	m := matrix.CreateBitMatrix(rows, cols)
+	for i := range rows {
+		for j := range cols {
+			m.Set(i, j, uint8(rng.Uint32()))
+		}
+	}
+	return m
+}
+
+// naiveMultiplyBits is the textbook definition of the matrix product over
+// GF(2)
+func naiveMultiplyBits(a, b *matrix.BitMatrix) *matrix.BitMatrix {
+	out := matrix.CreateBitMatrix(a.Rows(), b.Cols())
+	for i := range a.Rows() {
+		for j := range b.Cols() {
+			var sum uint8
+			for k := range a.Cols() {
+				sum ^= a.Get(i, k) & b.Get(k, j)
+			}
+			out.Set(i, j, sum)
+		}
+	}
+	return out
+}
+
+// rotationMatrix returns the matrix of a left rotation of an n-bit word by r
+func rotationMatrix(n, r int) *matrix.BitMatrix {
+	m := matrix.CreateBitMatrix(n, n)
+	for i := range n {
+		m.Set((i+r)%n, i, 1)
+	}
+	return m
+}
+
+func TestCreateBitMatrix(t *testing.T) {
+	m := matrix.CreateBitMatrix(3, 70)
+	rows, cols := m.Size()
+	assert.Equal(t, 3, rows)
+	assert.Equal(t, 3, m.Rows())
+	assert.Equal(t, 70, cols)
+	assert.Equal(t, 70, m.Cols())
+	for i := range rows {
+		for j := range cols {
+			assert.Zero(t, m.Get(i, j))
+		}
+	}
+
+	assert.PanicsWithValue(t, matrix.ErrInvalidDimensions, func() {
+		matrix.CreateBitMatrix(0, 1)
+	})
+	assert.PanicsWithValue(t, matrix.ErrInvalidDimensions, func() {
+		matrix.CreateBitMatrix(1, -1)
+	})
+}
+
+func TestCreateBitMatrixFromSlice(t *testing.T) {
+	m := matrix.CreateBitMatrixFromSlice([][]uint8{
+		{1, 0, 1},
+		{0, 3, 2},
+	})
+	assert.Equal(t, uint8(1), m.Get(0, 0))
+	assert.Equal(t, uint8(0), m.Get(0, 1))
+	assert.Equal(t, uint8(1), m.Get(0, 2))
+	assert.Equal(t, uint8(0), m.Get(1, 0))
+	assert.Equal(t, uint8(1), m.Get(1, 1))
+	assert.Equal(t, uint8(0), m.Get(1, 2))
+
+	assert.PanicsWithValue(t, matrix.ErrInvalidDimensions, func() {
+		matrix.CreateBitMatrixFromSlice(nil)
+	})
+	assert.PanicsWithValue(t, matrix.ErrInvalidDimensions, func() {
+		matrix.CreateBitMatrixFromSlice([][]uint8{{}})
+	})
+	assert.PanicsWithValue(t, matrix.ErrIncompatibleDataDimensions, func() {
+		matrix.CreateBitMatrixFromSlice([][]uint8{{1, 0}, {1}})
+	})
+}
+
+func TestBitMatrix_SetGet(t *testing.T) {
+	m := matrix.CreateBitMatrix(2, 130)
+	m.Set(1, 129, 1)
+	m.Set(0, 64, 1)
+	m.Set(0, 63, 1)
+	m.Set(0, 63, 0)
+	assert.Equal(t, uint8(1), m.Get(1, 129))
+	assert.Equal(t, uint8(1), m.Get(0, 64))
+	assert.Equal(t, uint8(0), m.Get(0, 63))
+
+	// Columns in the padding of the last word are out of range as well
+	assert.PanicsWithValue(t, "Index out of range", func() {
+		m.Get(0, 130)
+	})
+	assert.PanicsWithValue(t, "Index out of range", func() {
+		m.Set(2, 0, 1)
+	})
+	assert.PanicsWithValue(t, "Index out of range", func() {
+		m.Get(-1, 0)
+	})
+}
+
+func TestBitMatrix_Add(t *testing.T) {
+	a := matrix.CreateBitMatrixFromSlice([][]uint8{{1, 0}, {1, 1}})
+	b := matrix.CreateBitMatrixFromSlice([][]uint8{{1, 1}, {0, 1}})
+	assert.Same(t, a, a.Add(b))
+	assert.Equal(t, matrix.CreateBitMatrixFromSlice([][]uint8{{0, 1}, {1, 0}}), a)
+
+	// Self references cancel out in pairs
+	a.Add(a, b, a)
+	assert.Equal(t, matrix.CreateBitMatrixFromSlice([][]uint8{{1, 0}, {1, 1}}), a)
+	a.Add(a)
+	assert.Equal(t, matrix.CreateBitMatrix(2, 2), a)
+
+	assert.PanicsWithValue(t, matrix.ErrIncompatibleMatrixDimensions, func() {
+		a.Add(matrix.CreateBitMatrix(2, 3))
+	})
+}
+
+func TestBitMatrix_Multiply(t *testing.T) {
+	for _, size := range [][3]int{{1, 1, 1}, {3, 5, 2}, {70, 130, 65}} {
+		a := randomBitMatrix(size[0], size[1], 1)
+		b := randomBitMatrix(size[1], size[2], 2)
+		assert.Equal(t, naiveMultiplyBits(a, b), a.Multiply(b))
+	}
+
+	// Rotations compose
+	assert.Equal(t, rotationMatrix(16, 10), rotationMatrix(16, 7).Multiply(rotationMatrix(16, 3)))
+	assert.Equal(t, matrix.IdentityBitMatrix(16), rotationMatrix(16, 7).Multiply(rotationMatrix(16, 9)))
+
+	assert.PanicsWithValue(t, matrix.ErrIncompatibleMatrixDimensions, func() {
+		randomBitMatrix(2, 3, 1).Multiply(randomBitMatrix(2, 3, 1))
+	})
+}
+
+func TestBitMatrix_MultiplyVector(t *testing.T) {
+	r := rotationMatrix(64, 3)
+	x := uint64(0x0123456789abcdef)
+	assert.Equal(t, []uint64{bits.RotateLeft64(x, 3)}, r.MultiplyVector([]uint64{x}))
+
+	m := randomBitMatrix(70, 100, 3)
+	v := randomBitMatrix(100, 1, 4)
+	packed := make([]uint64, 2)
+	for i := range 100 {
+		packed[i/64] |= uint64(v.Get(i, 0)) << (i % 64)
+	}
+	product := m.Multiply(v)
+	result := m.MultiplyVector(packed)
+	assert.Len(t, result, 2)
+	for i := range 70 {
+		assert.Equal(t, product.Get(i, 0), uint8(result[i/64]>>(i%64)&1))
+	}
+
+	assert.PanicsWithValue(t, matrix.ErrIncompatibleDataDimensions, func() {
+		m.MultiplyVector([]uint64{1})
+	})
+}
+
+func TestBitMatrix_Transpose(t *testing.T) {
+	m := randomBitMatrix(70, 130, 5)
+	tr := m.Transpose()
+	assert.Equal(t, 130, tr.Rows())
+	assert.Equal(t, 70, tr.Cols())
+	for i := range 70 {
+		for j := range 130 {
+			assert.Equal(t, m.Get(i, j), tr.Get(j, i))
+		}
+	}
+	assert.Equal(t, m, tr.Transpose())
+	assert.Equal(t, rotationMatrix(32, 32-5), rotationMatrix(32, 5).Transpose())
+}
+
+func TestBitMatrix_Rank(t *testing.T) {
+	assert.Equal(t, 100, matrix.IdentityBitMatrix(100).Rank())
+	assert.Equal(t, 1, matrix.CreateBitMatrixFromSlice([][]uint8{{1, 1}, {1, 1}}).Rank())
+	assert.Equal(t, 0, matrix.CreateBitMatrix(3, 4).Rank())
+
+	// x ^ (x <<< 1) loses exactly the all ones vector
+	m := rotationMatrix(16, 1).Add(matrix.IdentityBitMatrix(16))
+	assert.Equal(t, 15, m.Rank())
+
+	// The rank is not changed and the matrix is not modified
+	before := m.Copy()
+	assert.Equal(t, 15, m.Transpose().Rank())
+	assert.Equal(t, before, m)
+}
+
+func TestBitMatrix_Inverse(t *testing.T) {
+	found := 0
+	for seed := range uint64(20) {
+		m := randomBitMatrix(67, 67, seed)
+		inverse, err := m.Inverse()
+		if m.Rank() < 67 {
+			assert.ErrorIs(t, err, matrix.ErrSingular)
+			continue
+		}
+		found++
+		assert.Nil(t, err)
+		assert.Equal(t, matrix.IdentityBitMatrix(67), m.Multiply(inverse))
+		assert.Equal(t, matrix.IdentityBitMatrix(67), inverse.Multiply(m))
+	}
+	assert.Greater(t, found, 0)
+
+	inverse, err := rotationMatrix(48, 8).Inverse()
+	assert.Nil(t, err)
+	assert.Equal(t, rotationMatrix(48, 40), inverse)
+
+	_, err = rotationMatrix(16, 1).Add(matrix.IdentityBitMatrix(16)).Inverse()
+	assert.ErrorIs(t, err, matrix.ErrSingular)
+	_, err = matrix.CreateBitMatrix(2, 3).Inverse()
+	assert.ErrorIs(t, err, matrix.ErrIncompatibleMatrixDimensions)
+}
+
+func TestBitMatrix_Solve(t *testing.T) {
+	// Underdetermined and overdetermined systems with a known solution
+	for _, size := range [][2]int{{40, 90}, {90, 40}, {64, 64}} {
+		a := randomBitMatrix(size[0], size[1], 6)
+		x := randomBitMatrix(size[1], 3, 7)
+		b := a.Multiply(x)
+
+		solution, err := a.Solve(b)
+		assert.Nil(t, err)
+		assert.Equal(t, size[1], solution.Rows())
+		assert.Equal(t, 3, solution.Cols())
+		assert.Equal(t, b, a.Multiply(solution))
+	}
+
+	// x ^ (x <<< 1) always has an even number of ones
+	m := rotationMatrix(16, 1).Add(matrix.IdentityBitMatrix(16))
+	b := matrix.CreateBitMatrix(16, 1)
+	b.Set(3, 0, 1)
+	_, err := m.Solve(b)
+	assert.ErrorIs(t, err, matrix.ErrNoSolution)
+	b.Set(9, 0, 1)
+	solution, err := m.Solve(b)
+	assert.Nil(t, err)
+	assert.Equal(t, b, m.Multiply(solution))
+
+	_, err = m.Solve(matrix.CreateBitMatrix(15, 1))
+	assert.ErrorIs(t, err, matrix.ErrIncompatibleMatrixDimensions)
+}
+
+func TestBitMatrix_Kernel(t *testing.T) {
+	assert.Nil(t, matrix.IdentityBitMatrix(10).Kernel())
+
+	m := rotationMatrix(16, 1).Add(matrix.IdentityBitMatrix(16))
+	kernel := m.Kernel()
+	assert.Equal(t, 1, kernel.Rows())
+	for j := range 16 {
+		assert.Equal(t, uint8(1), kernel.Get(0, j))
+	}
+
+	for _, size := range [][2]int{{40, 90}, {90, 40}, {30, 30}} {
+		a := randomBitMatrix(size[0], size[1], 8)
+		kernel := a.Kernel()
+		dimension := size[1] - a.Rank()
+		if dimension == 0 {
+			assert.Nil(t, kernel)
+			continue
+		}
+		assert.Equal(t, dimension, kernel.Rows())
+		assert.Equal(t, dimension, kernel.Rank())
+		assert.Equal(t, matrix.CreateBitMatrix(size[0], dimension), a.Multiply(kernel.Transpose()))
+	}
+
+	zero := matrix.CreateBitMatrix(2, 5)
+	assert.Equal(t, matrix.IdentityBitMatrix(5), zero.Kernel())
+}
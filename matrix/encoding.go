@@ -0,0 +1,564 @@
+package matrix
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+var (
+	ErrInvalidEncoding  = errors.New("Invalid matrix encoding")
+	ErrIncompatibleType = errors.New("Encoded values don't match the matrix type")
+)
+
+// binaryMagic starts every matrix encoded with MarshalBinary
+var binaryMagic = [4]byte{'M', 'T', 'R', 'X'}
+
+// npyMagic starts every .npy file
+var npyMagic = [6]byte{0x93, 'N', 'U', 'M', 'P', 'Y'}
+
+// npyMaxHeaderSize is the largest .npy header that is accepted, the same limit
+// NumPy uses by default
+const npyMaxHeaderSize = 10000
+
+// dtype describes how values are encoded, using the kinds of NumPy: 'i' for
+// signed integers, 'u' for unsigned integers, 'f' for floats and 'c' for
+// complex numbers. size is the number of bytes of a value.
+type dtype struct {
+	kind byte
+	size int
+}
+
+// dtypeOf returns the dtype of the values of a Matrix[T]. The size of int,
+// uint and uintptr depends on the platform.
+func dtypeOf[T Number]() dtype {
+	t := reflect.TypeFor[T]()
+	d := dtype{size: int(t.Size())}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		d.kind = 'i'
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		d.kind = 'u'
+	case reflect.Float32, reflect.Float64:
+		d.kind = 'f'
+	default:
+		d.kind = 'c'
+	}
+	return d
+}
+
+// wordSize returns the size of the words a value consists of. Complex numbers
+// consist of a real and an imaginary part that are encoded separately.
+func (d dtype) wordSize() int {
+	if d.kind == 'c' {
+		return d.size / 2
+	}
+	return d.size
+}
+
+// encodeValues writes the values to dst in the given byte order. dst must
+// hold at least len(values) * dtype size bytes.
+func encodeValues[T Number](dst []byte, values []T, order binary.ByteOrder) {
+	if len(values) == 0 {
+		return
+	}
+	// All Number types consist of one or two fixed size words, so the values
+	// can be accessed as a slice of unsigned integers of the word size
+	d := dtypeOf[T]()
+	n := len(values) * d.size / d.wordSize()
+	p := unsafe.Pointer(unsafe.SliceData(values))
+	switch d.wordSize() {
+	case 1:
+		copy(dst, unsafe.Slice((*byte)(p), n))
+	case 2:
+		for i, w := range unsafe.Slice((*uint16)(p), n) {
+			order.PutUint16(dst[2*i:], w)
+		}
+	case 4:
+		for i, w := range unsafe.Slice((*uint32)(p), n) {
+			order.PutUint32(dst[4*i:], w)
+		}
+	default:
+		for i, w := range unsafe.Slice((*uint64)(p), n) {
+			order.PutUint64(dst[8*i:], w)
+		}
+	}
+}
+
+// decodeValues reads the values from src in the given byte order. src must
+// hold at least len(values) * dtype size bytes.
+func decodeValues[T Number](values []T, src []byte, order binary.ByteOrder) {
+	if len(values) == 0 {
+		return
+	}
+	d := dtypeOf[T]()
+	n := len(values) * d.size / d.wordSize()
+	p := unsafe.Pointer(unsafe.SliceData(values))
+	switch d.wordSize() {
+	case 1:
+		copy(unsafe.Slice((*byte)(p), n), src)
+	case 2:
+		words := unsafe.Slice((*uint16)(p), n)
+		for i := range words {
+			words[i] = order.Uint16(src[2*i:])
+		}
+	case 4:
+		words := unsafe.Slice((*uint32)(p), n)
+		for i := range words {
+			words[i] = order.Uint32(src[4*i:])
+		}
+	default:
+		words := unsafe.Slice((*uint64)(p), n)
+		for i := range words {
+			words[i] = order.Uint64(src[8*i:])
+		}
+	}
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler for Matrix. The encoding
+// starts with the magic "MTRX", the kind ('i', 'u', 'f' or 'c') and size in
+// bytes of the values, followed by the number of rows and columns as unsigned
+// varints. The values follow row by row in little endian byte order.
+func (m *Matrix[T]) MarshalBinary() ([]byte, error) {
+	d := dtypeOf[T]()
+	data := make([]byte, 0, len(binaryMagic)+2+2*binary.MaxVarintLen64+m.rows*m.cols*d.size)
+	data = append(data, binaryMagic[:]...)
+	data = append(data, d.kind, byte(d.size))
+	data = binary.AppendUvarint(data, uint64(m.rows))
+	data = binary.AppendUvarint(data, uint64(m.cols))
+
+	offset := len(data)
+	data = data[:offset+m.rows*m.cols*d.size]
+	rowSize := m.cols * d.size
+	for i := range m.rows {
+		encodeValues(data[offset+i*rowSize:], m.row(i), binary.LittleEndian)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler for Matrix, creating a
+// matrix from data encoded with MarshalBinary.
+// Returns ErrInvalidEncoding if the data is malformed or ErrIncompatibleType
+// if the values were encoded from a different type.
+func (m *Matrix[T]) UnmarshalBinary(data []byte) error {
+	if len(data) < len(binaryMagic)+2 || !bytes.Equal(data[:len(binaryMagic)], binaryMagic[:]) {
+		return ErrInvalidEncoding
+	}
+	data = data[len(binaryMagic):]
+	d := dtypeOf[T]()
+	if data[0] != d.kind || int(data[1]) != d.size {
+		return ErrIncompatibleType
+	}
+	data = data[2:]
+
+	rows, n := binary.Uvarint(data)
+	if n <= 0 {
+		return ErrInvalidEncoding
+	}
+	data = data[n:]
+	cols, n := binary.Uvarint(data)
+	if n <= 0 {
+		return ErrInvalidEncoding
+	}
+	data = data[n:]
+
+	if rows == 0 || cols == 0 {
+		return ErrInvalidDimensions
+	}
+	if rows > uint64(len(data)) || cols > uint64(len(data))/rows || int(rows*cols)*d.size != len(data) {
+		return ErrInvalidEncoding
+	}
+
+	m.rows = int(rows)
+	m.cols = int(cols)
+	m.stride = m.cols
+	m.values = make([]T, m.rows*m.cols)
+	decodeValues(m.values, data, binary.LittleEndian)
+	return nil
+}
+
+// CreateFromBinary creates a new matrix from data encoded with MarshalBinary.
+// Returns ErrInvalidEncoding if the data is malformed or ErrIncompatibleType
+// if the values were encoded from a different type.
+func CreateFromBinary[T Number](data []byte) (*Matrix[T], error) {
+	m := &Matrix[T]{}
+	if err := m.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// formatValue formats v the way strconv formats values of its kind. Complex
+// numbers are formatted as (real+imaginaryi).
+func formatValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+	default:
+		return strconv.FormatComplex(v.Complex(), 'g', -1, v.Type().Bits())
+	}
+}
+
+// parseValue parses s and stores the result in v. Returns an error if s isn't
+// a valid value of the kind of v or out of range.
+func parseValue(v reflect.Value, s string) error {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		v.SetInt(x)
+		return err
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		x, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		v.SetUint(x)
+		return err
+	case reflect.Float32, reflect.Float64:
+		x, err := strconv.ParseFloat(s, v.Type().Bits())
+		v.SetFloat(x)
+		return err
+	default:
+		x, err := strconv.ParseComplex(s, v.Type().Bits())
+		v.SetComplex(x)
+		return err
+	}
+}
+
+// WriteCSV writes the matrix to w as comma separated values, one line per row.
+// Values are formatted with the shortest representation that parses back to
+// the same value.
+func (m *Matrix[T]) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	record := make([]string, m.cols)
+	for i := range m.rows {
+		row := reflect.ValueOf(m.row(i))
+		for j := range record {
+			record[j] = formatValue(row.Index(j))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// CreateFromCSV creates a new matrix from comma separated values read from r,
+// with one row per line. Surrounding spaces of the values are ignored.
+// Returns ErrInvalidDimensions if there are no values,
+// ErrIncompatibleDataDimensions if the lines have inconsistent lengths, or
+// any error encountered while reading or parsing the values.
+func CreateFromCSV[T Number](r io.Reader) (*Matrix[T], error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.ReuseRecord = true
+
+	m := &Matrix[T]{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if m.rows == 0 {
+			m.cols = len(record)
+		} else if len(record) != m.cols {
+			return nil, ErrIncompatibleDataDimensions
+		}
+
+		row := make([]T, m.cols)
+		values := reflect.ValueOf(row)
+		for j, field := range record {
+			if err := parseValue(values.Index(j), strings.TrimSpace(field)); err != nil {
+				return nil, err
+			}
+		}
+		m.values = append(m.values, row...)
+		m.rows++
+	}
+
+	if m.rows == 0 || m.cols == 0 {
+		return nil, ErrInvalidDimensions
+	}
+	m.stride = m.cols
+	return m, nil
+}
+
+// WriteNpy writes the matrix to w in the NumPy .npy format (version 1.0) as
+// a two-dimensional array in little endian byte order. int, uint and uintptr
+// values are written with their size on the current platform.
+func (m *Matrix[T]) WriteNpy(w io.Writer) error {
+	d := dtypeOf[T]()
+	order := '<'
+	if d.size == 1 {
+		order = '|'
+	}
+	header := fmt.Sprintf("{'descr': '%c%c%d', 'fortran_order': False, 'shape': (%d, %d), }",
+		order, d.kind, d.size, m.rows, m.cols)
+
+	// The header is padded with spaces and terminated by a newline so that
+	// the data starts at a multiple of 64 bytes
+	preamble := len(npyMagic) + 4
+	padding := 63 - (preamble+len(header))%64
+	header += strings.Repeat(" ", padding) + "\n"
+
+	bw := bufio.NewWriter(w)
+	bw.Write(npyMagic[:])
+	bw.Write([]byte{1, 0})
+	binary.Write(bw, binary.LittleEndian, uint16(len(header)))
+	bw.WriteString(header)
+
+	buf := make([]byte, m.cols*d.size)
+	for i := range m.rows {
+		encodeValues(buf, m.row(i), binary.LittleEndian)
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// CreateFromNpy creates a new matrix from an array in the NumPy .npy format
+// read from r. One-dimensional arrays become a matrix with a single row.
+// Arrays in Fortran order and both byte orders are supported.
+// Returns ErrInvalidEncoding if the data isn't a valid .npy file,
+// ErrIncompatibleType if the dtype of the array doesn't match T,
+// ErrInvalidDimensions if the array is empty, too large or has more than two
+// dimensions, io.ErrUnexpectedEOF if there is less data than the shape
+// requires or any error encountered while reading.
+func CreateFromNpy[T Number](r io.Reader) (*Matrix[T], error) {
+	var preamble [len(npyMagic) + 2]byte
+	if _, err := io.ReadFull(r, preamble[:]); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(preamble[:len(npyMagic)], npyMagic[:]) {
+		return nil, ErrInvalidEncoding
+	}
+
+	var headerSize uint32
+	switch preamble[len(npyMagic)] {
+	case 1:
+		var size uint16
+		if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+			return nil, err
+		}
+		headerSize = uint32(size)
+	case 2, 3:
+		if err := binary.Read(r, binary.LittleEndian, &headerSize); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrInvalidEncoding
+	}
+	if headerSize > npyMaxHeaderSize {
+		return nil, ErrInvalidEncoding
+	}
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	h, err := parseNpyHeader(string(header))
+	if err != nil {
+		return nil, err
+	}
+	order, err := h.byteOrder(dtypeOf[T]())
+	if err != nil {
+		return nil, err
+	}
+
+	rows, cols := 1, 0
+	switch len(h.shape) {
+	case 1:
+		cols = h.shape[0]
+	case 2:
+		rows, cols = h.shape[0], h.shape[1]
+	}
+	// The shape comes from the file, so the size in bytes must not overflow
+	size := dtypeOf[T]().size
+	if rows < 1 || cols < 1 || rows > math.MaxInt/cols/size {
+		return nil, ErrInvalidDimensions
+	}
+
+	// The shape comes from the file as well, so only allocate the matrix
+	// once all data has been read. ReadAll only grows its buffer as the data
+	// arrives.
+	data, err := io.ReadAll(io.LimitReader(r, int64(rows*cols*size)))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != rows*cols*size {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	// Arrays in Fortran order are stored column by column, which are the
+	// rows of the transpose
+	if h.fortran {
+		rows, cols = cols, rows
+	}
+	m := Create[T](rows, cols)
+	decodeValues(m.values, data, order)
+
+	if h.fortran {
+		return m.Transpose(), nil
+	}
+	return m, nil
+}
+
+// npyHeader holds the fields of the header of a .npy file
+type npyHeader struct {
+	descr   string
+	fortran bool
+	shape   []int
+}
+
+// byteOrder returns the byte order of the values if the descr of the header
+// matches d. Returns ErrIncompatibleType otherwise.
+func (h *npyHeader) byteOrder(d dtype) (binary.ByteOrder, error) {
+	if len(h.descr) < 3 || h.descr[1] != d.kind || h.descr[2:] != strconv.Itoa(d.size) {
+		return nil, ErrIncompatibleType
+	}
+	switch h.descr[0] {
+	case '<':
+		return binary.LittleEndian, nil
+	case '>':
+		return binary.BigEndian, nil
+	case '=':
+		return binary.NativeEndian, nil
+	case '|':
+		if d.size == 1 {
+			return binary.LittleEndian, nil
+		}
+	}
+	return nil, ErrIncompatibleType
+}
+
+// parseNpyHeader parses the header of a .npy file, which is a Python dict
+// literal with the keys descr, fortran_order and shape
+func parseNpyHeader(header string) (*npyHeader, error) {
+	p := &npyParser{s: strings.TrimSpace(header)}
+	h := &npyHeader{}
+	seen := 0
+
+	if !p.consume('{') {
+		return nil, ErrInvalidEncoding
+	}
+	for !p.consume('}') {
+		key, ok := p.string()
+		if !ok || !p.consume(':') {
+			return nil, ErrInvalidEncoding
+		}
+		switch key {
+		case "descr":
+			h.descr, ok = p.string()
+		case "fortran_order":
+			h.fortran, ok = p.bool()
+		case "shape":
+			h.shape, ok = p.tuple()
+		default:
+			ok = false
+		}
+		if !ok {
+			return nil, ErrInvalidEncoding
+		}
+		seen++
+		if !p.consume(',') && !p.peek('}') {
+			return nil, ErrInvalidEncoding
+		}
+	}
+	if seen != 3 || p.s != "" {
+		return nil, ErrInvalidEncoding
+	}
+	return h, nil
+}
+
+// npyParser parses the Python literals that occur in .npy headers
+type npyParser struct {
+	s string
+}
+
+// skip removes leading whitespace
+func (p *npyParser) skip() {
+	p.s = strings.TrimLeft(p.s, " \t\r\n")
+}
+
+// peek returns true if the next character is c
+func (p *npyParser) peek(c byte) bool {
+	p.skip()
+	return len(p.s) > 0 && p.s[0] == c
+}
+
+// consume removes the next character if it is c. Returns true if it did.
+func (p *npyParser) consume(c byte) bool {
+	if !p.peek(c) {
+		return false
+	}
+	p.s = p.s[1:]
+	return true
+}
+
+// string parses a string in single or double quotes
+func (p *npyParser) string() (string, bool) {
+	p.skip()
+	if len(p.s) == 0 || (p.s[0] != '\'' && p.s[0] != '"') {
+		return "", false
+	}
+	end := strings.IndexByte(p.s[1:], p.s[0])
+	if end < 0 {
+		return "", false
+	}
+	s := p.s[1 : end+1]
+	p.s = p.s[end+2:]
+	return s, true
+}
+
+// bool parses True or False
+func (p *npyParser) bool() (bool, bool) {
+	p.skip()
+	for _, literal := range []string{"False", "True"} {
+		if rest, ok := strings.CutPrefix(p.s, literal); ok {
+			p.s = rest
+			return literal == "True", true
+		}
+	}
+	return false, false
+}
+
+// tuple parses a tuple of non-negative integers
+func (p *npyParser) tuple() ([]int, bool) {
+	if !p.consume('(') {
+		return nil, false
+	}
+	values := []int{}
+	for !p.consume(')') {
+		p.skip()
+		end := strings.IndexAny(p.s, ",) \t\r\n")
+		if end < 0 {
+			return nil, false
+		}
+		v, err := strconv.Atoi(p.s[:end])
+		if err != nil || v < 0 {
+			return nil, false
+		}
+		values = append(values, v)
+		p.s = p.s[end:]
+		if !p.consume(',') && !p.peek(')') {
+			return nil, false
+		}
+	}
+	return values, true
+}
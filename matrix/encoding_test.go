@@ -0,0 +1,252 @@
+package matrix_test
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*matrix.Matrix[int])(nil)
+	_ encoding.BinaryUnmarshaler = (*matrix.Matrix[int])(nil)
+)
+
+func testBinaryRoundTrip[T matrix.Number](t *testing.T, m *matrix.Matrix[T]) {
+	t.Helper()
+
+	data, err := m.MarshalBinary()
+	assert.Nil(t, err)
+	out, err := matrix.CreateFromBinary[T](data)
+	assert.Nil(t, err)
+	assert.Equal(t, m.Copy(), out)
+}
+
+func TestMatrix_MarshalBinary(t *testing.T) {
+	m := matrix.CreateFromSlice([][]int16{{1, -2}})
+	data, err := m.MarshalBinary()
+	assert.Nil(t, err)
+	assert.Equal(t, []byte{'M', 'T', 'R', 'X', 'i', 2, 1, 2, 0x01, 0x00, 0xfe, 0xff}, data)
+
+	testBinaryRoundTrip(t, matrix.CreateFromSlice([][]int8{{-128, 127}, {0, 1}}))
+	testBinaryRoundTrip(t, matrix.CreateFromSlice([][]uint16{{0xbeef, 1, 2}}))
+	testBinaryRoundTrip(t, matrix.CreateFromSlice([][]int{{math.MinInt, math.MaxInt}, {-1, 0}}))
+	testBinaryRoundTrip(t, matrix.CreateFromSlice([][]uint64{{math.MaxUint64}}))
+	testBinaryRoundTrip(t, matrix.CreateFromSlice([][]float32{{1.5, float32(math.Inf(-1))}}))
+	testBinaryRoundTrip(t, matrix.CreateFromSlice([][]float64{{math.Pi}, {-0.1}}))
+	testBinaryRoundTrip(t, matrix.CreateFromSlice([][]complex64{{1 + 2i, -3i}}))
+	testBinaryRoundTrip(t, matrix.CreateFromSlice([][]complex128{{1 + 2i}, {complex(math.E, -math.Pi)}}))
+
+	// Views are encoded without the values outside the view
+	big := matrix.Create[uint32](300, 200)
+	for i := range 300 {
+		for j := range 200 {
+			big.Set(i, j, uint32(i*200+j))
+		}
+	}
+	testBinaryRoundTrip(t, big)
+	testBinaryRoundTrip(t, big.SubMatrix(10, 20, 30, 40))
+	testBinaryRoundTrip(t, big.Col(7))
+}
+
+func TestMatrix_UnmarshalBinary(t *testing.T) {
+	data, err := matrix.CreateFromSlice([][]int32{{1, 2}, {3, 4}}).MarshalBinary()
+	assert.Nil(t, err)
+
+	var m matrix.Matrix[int32]
+	assert.Nil(t, m.UnmarshalBinary(data))
+	assert.Equal(t, int32(3), m.Get(1, 0))
+
+	_, err = matrix.CreateFromBinary[uint32](data)
+	assert.ErrorIs(t, err, matrix.ErrIncompatibleType)
+	_, err = matrix.CreateFromBinary[int64](data)
+	assert.ErrorIs(t, err, matrix.ErrIncompatibleType)
+	_, err = matrix.CreateFromBinary[float32](data)
+	assert.ErrorIs(t, err, matrix.ErrIncompatibleType)
+
+	_, err = matrix.CreateFromBinary[int32](data[:len(data)-1])
+	assert.ErrorIs(t, err, matrix.ErrInvalidEncoding)
+	_, err = matrix.CreateFromBinary[int32](append(data, 0))
+	assert.ErrorIs(t, err, matrix.ErrInvalidEncoding)
+	_, err = matrix.CreateFromBinary[int32](data[:7])
+	assert.ErrorIs(t, err, matrix.ErrInvalidEncoding)
+	_, err = matrix.CreateFromBinary[int32](nil)
+	assert.ErrorIs(t, err, matrix.ErrInvalidEncoding)
+	_, err = matrix.CreateFromBinary[int32]([]byte("JSON[[1]]"))
+	assert.ErrorIs(t, err, matrix.ErrInvalidEncoding)
+
+	_, err = matrix.CreateFromBinary[int32]([]byte{'M', 'T', 'R', 'X', 'i', 4, 0, 1})
+	assert.ErrorIs(t, err, matrix.ErrInvalidDimensions)
+
+	// Dimensions that overflow must not cause huge allocations
+	huge := []byte{'M', 'T', 'R', 'X', 'i', 4}
+	huge = binary.AppendUvarint(huge, 1<<40)
+	huge = binary.AppendUvarint(huge, 1<<40)
+	_, err = matrix.CreateFromBinary[int32](append(huge, 0, 0, 0, 0))
+	assert.ErrorIs(t, err, matrix.ErrInvalidEncoding)
+}
+
+func TestMatrix_WriteCSV(t *testing.T) {
+	var b strings.Builder
+	m := matrix.CreateFromSlice([][]int{{1, -2, 3}, {4, 5, 6}})
+	assert.Nil(t, m.WriteCSV(&b))
+	assert.Equal(t, "1,-2,3\n4,5,6\n", b.String())
+
+	b.Reset()
+	mf := matrix.CreateFromSlice([][]float64{{0.1, 1e-300}, {math.Inf(1), -2}})
+	assert.Nil(t, mf.WriteCSV(&b))
+	assert.Equal(t, "0.1,1e-300\n+Inf,-2\n", b.String())
+
+	out, err := matrix.CreateFromCSV[float64](strings.NewReader(b.String()))
+	assert.Nil(t, err)
+	assert.Equal(t, mf, out)
+
+	b.Reset()
+	mc := matrix.CreateFromSlice([][]complex64{{1 + 2i, -0.5i}})
+	assert.Nil(t, mc.WriteCSV(&b))
+	assert.Equal(t, "(1+2i),(0-0.5i)\n", b.String())
+
+	outc, err := matrix.CreateFromCSV[complex64](strings.NewReader(b.String()))
+	assert.Nil(t, err)
+	assert.Equal(t, mc, outc)
+}
+
+func TestCreateFromCSV(t *testing.T) {
+	m, err := matrix.CreateFromCSV[uint8](strings.NewReader("1, 2 ,3\r\n4,5,255\n"))
+	assert.Nil(t, err)
+	assert.Equal(t, matrix.CreateFromSlice([][]uint8{{1, 2, 3}, {4, 5, 255}}), m)
+
+	// Out of range
+	_, err = matrix.CreateFromCSV[uint8](strings.NewReader("256\n"))
+	assert.NotNil(t, err)
+	_, err = matrix.CreateFromCSV[int](strings.NewReader("1.5\n"))
+	assert.NotNil(t, err)
+
+	_, err = matrix.CreateFromCSV[int](strings.NewReader(""))
+	assert.ErrorIs(t, err, matrix.ErrInvalidDimensions)
+	_, err = matrix.CreateFromCSV[int](strings.NewReader("1,2\n3\n"))
+	assert.ErrorIs(t, err, matrix.ErrIncompatibleDataDimensions)
+}
+
+// npyFile returns a .npy file version 1.0 with the given header and data
+func npyFile(header string, data ...byte) []byte {
+	padding := 63 - (10+len(header))%64
+	header += strings.Repeat(" ", padding) + "\n"
+
+	file := []byte("\x93NUMPY\x01\x00")
+	file = binary.LittleEndian.AppendUint16(file, uint16(len(header)))
+	file = append(file, header...)
+	return append(file, data...)
+}
+
+func TestMatrix_WriteNpy(t *testing.T) {
+	// Equal to np.save of np.array([[1, 2, 3], [4, 5, -1]], dtype="<i4")
+	expected := npyFile("{'descr': '<i4', 'fortran_order': False, 'shape': (2, 3), }",
+		1, 0, 0, 0, 2, 0, 0, 0, 3, 0, 0, 0, 4, 0, 0, 0, 5, 0, 0, 0, 0xff, 0xff, 0xff, 0xff)
+	assert.Len(t, expected, 128+24)
+
+	var b bytes.Buffer
+	m := matrix.CreateFromSlice([][]int32{{1, 2, 3}, {4, 5, -1}})
+	assert.Nil(t, m.WriteNpy(&b))
+	assert.Equal(t, expected, b.Bytes())
+
+	b.Reset()
+	assert.Nil(t, matrix.CreateFromSlice([][]uint8{{7}}).WriteNpy(&b))
+	assert.Equal(t, npyFile("{'descr': '|u1', 'fortran_order': False, 'shape': (1, 1), }", 7), b.Bytes())
+
+	b.Reset()
+	mc := matrix.CreateFromSlice([][]complex128{{1 + 2i, 3}, {-4i, 5.5}})
+	assert.Nil(t, mc.SubMatrix(0, 1, 2, 1).WriteNpy(&b))
+	assert.True(t, bytes.HasPrefix(b.Bytes()[10:], []byte("{'descr': '<c16', 'fortran_order': False, 'shape': (2, 1), }")))
+	out, err := matrix.CreateFromNpy[complex128](&b)
+	assert.Nil(t, err)
+	assert.Equal(t, matrix.CreateFromSlice([][]complex128{{3}, {5.5}}), out)
+}
+
+func TestCreateFromNpy(t *testing.T) {
+	// Big endian Fortran order float64 array as written by NumPy
+	file := npyFile(`{'descr': '>f8', 'fortran_order': True, 'shape': (2, 2), }`)
+	for _, v := range []float64{1, 3, 2, 4} {
+		file = binary.BigEndian.AppendUint64(file, math.Float64bits(v))
+	}
+	m, err := matrix.CreateFromNpy[float64](bytes.NewReader(file))
+	assert.Nil(t, err)
+	assert.Equal(t, matrix.CreateFromSlice([][]float64{{1, 2}, {3, 4}}), m)
+
+	_, err = matrix.CreateFromNpy[float32](bytes.NewReader(file))
+	assert.ErrorIs(t, err, matrix.ErrIncompatibleType)
+	_, err = matrix.CreateFromNpy[uint64](bytes.NewReader(file))
+	assert.ErrorIs(t, err, matrix.ErrIncompatibleType)
+
+	// One-dimensional arrays become a row, the order of keys doesn't matter
+	file = npyFile(`{"shape": (3,), "fortran_order": False, "descr": "|i1"}`, 1, 0xff, 3)
+	mi, err := matrix.CreateFromNpy[int8](bytes.NewReader(file))
+	assert.Nil(t, err)
+	assert.Equal(t, matrix.CreateFromSlice([][]int8{{1, -1, 3}}), mi)
+
+	// Version 2.0 has a four byte header length
+	file = []byte("\x93NUMPY\x02\x00")
+	header := "{'descr': '<u2', 'fortran_order': False, 'shape': (1, 2), }\n"
+	file = binary.LittleEndian.AppendUint32(file, uint32(len(header)))
+	file = append(append(file, header...), 0xef, 0xbe, 1, 0)
+	mu, err := matrix.CreateFromNpy[uint16](bytes.NewReader(file))
+	assert.Nil(t, err)
+	assert.Equal(t, matrix.CreateFromSlice([][]uint16{{0xbeef, 1}}), mu)
+
+	for _, header := range []string{
+		`{'descr': '<i1', 'fortran_order': False, 'shape': (), }`,
+		`{'descr': '<i1', 'fortran_order': False, 'shape': (0, 2), }`,
+		`{'descr': '<i1', 'fortran_order': False, 'shape': (1, 1, 1), }`,
+		`{'descr': '<i1', 'fortran_order': False, 'shape': (1099511627776, 1099511627776), }`,
+		`{'descr': '<i1', 'fortran_order': True, 'shape': (9223372036854775807, 2), }`,
+	} {
+		_, err = matrix.CreateFromNpy[int8](bytes.NewReader(npyFile(header, 1)))
+		assert.ErrorIs(t, err, matrix.ErrInvalidDimensions, header)
+	}
+
+	for _, header := range []string{
+		`{'descr': '<i1', 'fortran_order': False}`,
+		`{'descr': '<i1', 'fortran_order': No, 'shape': (1,), }`,
+		`{'descr': '<i1', 'fortran_order': False, 'shape': (-1,), }`,
+		`{'descr': '<i1', 'fortran_order': False, 'shape': (1,), 'extra': 1}`,
+		`{'descr': '<i1' 'fortran_order': False, 'shape': (1,)}`,
+		`['descr', 'fortran_order', 'shape']`,
+	} {
+		_, err = matrix.CreateFromNpy[int8](bytes.NewReader(npyFile(header, 1)))
+		assert.ErrorIs(t, err, matrix.ErrInvalidEncoding, header)
+	}
+
+	// The size in bytes overflows even though the number of values doesn't
+	file = npyFile(`{'descr': '<i8', 'fortran_order': False, 'shape': (2147483648, 2147483648), }`, 1)
+	_, err = matrix.CreateFromNpy[int64](bytes.NewReader(file))
+	assert.ErrorIs(t, err, matrix.ErrInvalidDimensions)
+
+	_, err = matrix.CreateFromNpy[int8](strings.NewReader("not a numpy file"))
+	assert.ErrorIs(t, err, matrix.ErrInvalidEncoding)
+
+	// Truncated data
+	file = npyFile(`{'descr': '<i4', 'fortran_order': False, 'shape': (2, 2), }`, 1, 2, 3)
+	_, err = matrix.CreateFromNpy[int32](bytes.NewReader(file))
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// A huge shape in a tiny file must not allocate the matrix
+	for _, header := range []string{
+		`{'descr': '<i8', 'fortran_order': False, 'shape': (200000, 200000), }`,
+		`{'descr': '<i8', 'fortran_order': True, 'shape': (200000, 200000), }`,
+		`{'descr': '<i8', 'fortran_order': False, 'shape': (40000000000,), }`,
+	} {
+		_, err = matrix.CreateFromNpy[int64](bytes.NewReader(npyFile(header, 1, 2, 3, 4, 5, 6, 7, 8)))
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF, header)
+	}
+
+	// A huge header size in a tiny file must not allocate the header
+	file = binary.LittleEndian.AppendUint32([]byte("\x93NUMPY\x02\x00"), math.MaxUint32)
+	_, err = matrix.CreateFromNpy[int64](bytes.NewReader(file))
+	assert.ErrorIs(t, err, matrix.ErrInvalidEncoding)
+}
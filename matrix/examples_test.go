@@ -2,6 +2,7 @@ package matrix_test
 
 import (
 	"fmt"
+	"sync"
 
 	"git.omicron.one/playground/cryptography/matrix"
 )
@@ -42,3 +43,69 @@ func ExampleMatrix_Apply() {
 		return in * in
 	})
 }
+
+func ExamplePower() {
+	// Two step transition probabilities of a Markov chain
+	p := matrix.CreateFromSlice([][]float64{
+		{0.5, 0.5},
+		{0.25, 0.75},
+	})
+	p2 := matrix.Power(p, 2)
+
+	for i := range p2.Rows() {
+		for j := range p2.Cols() {
+			fmt.Printf(" %.4f", p2.Get(i, j))
+		}
+		fmt.Println()
+	}
+
+	// Output:
+	//  0.3750 0.6250
+	//  0.3125 0.6875
+}
+
+func ExampleBitMatrix_Inverse() {
+	// The linear map x -> x ^ (x <<< 2) ^ (x <<< 7) on 16-bit words
+	m := matrix.IdentityBitMatrix(16)
+	for _, r := range []int{2, 7} {
+		rotation := matrix.CreateBitMatrix(16, 16)
+		for i := range 16 {
+			rotation.Set((i+r)%16, i, 1)
+		}
+		m.Add(rotation)
+	}
+
+	inverse, err := m.Inverse()
+	fmt.Println(m.Rank(), err)
+
+	x := []uint64{0xbeef}
+	y := m.MultiplyVector(x)
+	fmt.Printf("%04x %04x\n", y[0], inverse.MultiplyVector(y)[0])
+
+	// Output:
+	// 16 <nil>
+	// 328e beef
+}
+
+func ExampleAccumulator() {
+	// Count the values of a byte sequence with four workers
+	data := []byte("abracadabra")
+	counts := matrix.NewAccumulator[int](1, 256)
+
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			shard := counts.Shard()
+			for i := w; i < len(data); i += 4 {
+				shard.Set(0, int(data[i]), shard.Get(0, int(data[i]))+1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	total := counts.Merge()
+	fmt.Println(total.Get(0, 'a'), total.Get(0, 'b'), total.Get(0, 'r'), total.Total())
+	// Output: 5 2 2 11
+}
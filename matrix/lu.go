@@ -0,0 +1,241 @@
+package matrix
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// Field is the set of types that support the decompositions of this package.
+// Integer types are excluded because their division is not exact.
+type Field interface {
+	float32 | float64 | complex64 | complex128
+}
+
+// LU is the LU decomposition with partial pivoting of a square matrix A,
+// P * A = L * U, where P is a permutation matrix, L is a lower triangular
+// matrix with ones on the diagonal and U is an upper triangular matrix.
+type LU[T Field] struct {
+	// lu holds L below and U on and above the diagonal
+	lu       *Matrix[T]
+	pivots   []int
+	sign     int
+	singular bool
+}
+
+// abs returns the absolute value of v
+func abs[T Field](v T) float64 {
+	switch x := any(v).(type) {
+	case float32:
+		return math.Abs(float64(x))
+	case float64:
+		return math.Abs(x)
+	case complex64:
+		return cmplx.Abs(complex128(x))
+	case complex128:
+		return cmplx.Abs(x)
+	}
+	panic("unreachable")
+}
+
+// epsilon returns the machine epsilon of the precision of T
+func epsilon[T Field]() float64 {
+	var zero T
+	switch any(zero).(type) {
+	case float32, complex64:
+		return 0x1p-23
+	}
+	return 0x1p-52
+}
+
+// DecomposeLU computes the LU decomposition with partial pivoting of m. The
+// decomposition of a singular matrix succeeds, but it can't be used to solve
+// systems or to compute the inverse. A matrix counts as singular if a pivot is
+// not larger than n * epsilon * max |m_ij|, where epsilon is the machine
+// epsilon of T.
+// Returns ErrIncompatibleMatrixDimensions if m is not square.
+func DecomposeLU[T Field](m *Matrix[T]) (*LU[T], error) {
+	if m.rows != m.cols {
+		return nil, ErrIncompatibleMatrixDimensions
+	}
+
+	n := m.rows
+	d := &LU[T]{
+		lu:     m.Copy(),
+		pivots: make([]int, n),
+		sign:   1,
+	}
+	for i := range n {
+		d.pivots[i] = i
+	}
+
+	largest := 0.0
+	for _, v := range d.lu.values {
+		largest = max(largest, abs(v))
+	}
+	tolerance := float64(n) * epsilon[T]() * largest
+
+	lu := d.lu
+	for k := range n {
+		p := k
+		for i := k + 1; i < n; i++ {
+			if abs(lu.values[i*n+k]) > abs(lu.values[p*n+k]) {
+				p = i
+			}
+		}
+		if p != k {
+			rowP, rowK := lu.row(p), lu.row(k)
+			for j := range rowK {
+				rowP[j], rowK[j] = rowK[j], rowP[j]
+			}
+			d.pivots[p], d.pivots[k] = d.pivots[k], d.pivots[p]
+			d.sign = -d.sign
+		}
+
+		pivot := lu.values[k*n+k]
+		if abs(pivot) <= tolerance {
+			d.singular = true
+			continue
+		}
+
+		rowK := lu.row(k)
+		for i := k + 1; i < n; i++ {
+			row := lu.row(i)
+			f := row[k] / pivot
+			row[k] = f
+			for j := k + 1; j < n; j++ {
+				row[j] -= f * rowK[j]
+			}
+		}
+	}
+	return d, nil
+}
+
+// L returns the lower triangular factor with ones on the diagonal
+func (d *LU[T]) L() *Matrix[T] {
+	n := d.lu.rows
+	l := Identity[T](n)
+	for i := range n {
+		copy(l.row(i)[:i], d.lu.row(i)[:i])
+	}
+	return l
+}
+
+// U returns the upper triangular factor
+func (d *LU[T]) U() *Matrix[T] {
+	n := d.lu.rows
+	u := Create[T](n, n)
+	for i := range n {
+		copy(u.row(i)[i:], d.lu.row(i)[i:])
+	}
+	return u
+}
+
+// P returns the permutation matrix
+func (d *LU[T]) P() *Matrix[T] {
+	n := d.lu.rows
+	p := Create[T](n, n)
+	for i, pivot := range d.pivots {
+		p.values[i*n+pivot] = 1
+	}
+	return p
+}
+
+// Singular returns true if the decomposed matrix is singular
+func (d *LU[T]) Singular() bool {
+	return d.singular
+}
+
+// Determinant returns the determinant of the decomposed matrix
+func (d *LU[T]) Determinant() T {
+	det := T(1)
+	if d.sign < 0 {
+		det = -det
+	}
+	for i := range d.lu.rows {
+		det *= d.lu.values[i*d.lu.stride+i]
+	}
+	return det
+}
+
+// Solve solves A * x = b for x, where b may have multiple columns to solve
+// several systems at once. Returns x as a new matrix.
+// Returns ErrIncompatibleMatrixDimensions if b doesn't have as many rows as A
+// or ErrSingular if A is singular.
+func (d *LU[T]) Solve(b *Matrix[T]) (*Matrix[T], error) {
+	n := d.lu.rows
+	if b.rows != n {
+		return nil, ErrIncompatibleMatrixDimensions
+	}
+	if d.singular {
+		return nil, ErrSingular
+	}
+
+	x := Create[T](n, b.cols)
+	for i, pivot := range d.pivots {
+		copy(x.row(i), b.row(pivot))
+	}
+
+	// Forward substitution with L, then back substitution with U, working on
+	// complete rows of x
+	for i := range n {
+		row := x.row(i)
+		for k, l := range d.lu.row(i)[:i] {
+			for j, v := range x.row(k) {
+				row[j] -= l * v
+			}
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		row := x.row(i)
+		lu := d.lu.row(i)
+		for k := i + 1; k < n; k++ {
+			u := lu[k]
+			for j, v := range x.row(k) {
+				row[j] -= u * v
+			}
+		}
+		for j := range row {
+			row[j] /= lu[i]
+		}
+	}
+	return x, nil
+}
+
+// Inverse returns the inverse of the decomposed matrix as a new matrix.
+// Returns ErrSingular if the matrix is singular.
+func (d *LU[T]) Inverse() (*Matrix[T], error) {
+	return d.Solve(Identity[T](d.lu.rows))
+}
+
+// Determinant returns the determinant of m.
+// Returns ErrIncompatibleMatrixDimensions if m is not square.
+func Determinant[T Field](m *Matrix[T]) (T, error) {
+	d, err := DecomposeLU(m)
+	if err != nil {
+		return 0, err
+	}
+	return d.Determinant(), nil
+}
+
+// Inverse returns the inverse of m as a new matrix.
+// Returns ErrIncompatibleMatrixDimensions if m is not square or ErrSingular if
+// m is singular.
+func Inverse[T Field](m *Matrix[T]) (*Matrix[T], error) {
+	d, err := DecomposeLU(m)
+	if err != nil {
+		return nil, err
+	}
+	return d.Inverse()
+}
+
+// Solve solves a * x = b for x and returns it as a new matrix. b may have
+// multiple columns to solve several systems at once.
+// Returns ErrIncompatibleMatrixDimensions if a is not square or b doesn't have
+// as many rows as a, or ErrSingular if a is singular.
+func Solve[T Field](a, b *Matrix[T]) (*Matrix[T], error) {
+	d, err := DecomposeLU(a)
+	if err != nil {
+		return nil, err
+	}
+	return d.Solve(b)
+}
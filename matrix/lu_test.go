@@ -0,0 +1,201 @@
+package matrix_test
+
+import (
+	"math/cmplx"
+	"math/rand/v2"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+// assertMatrixInDelta checks that all elements of expected and actual differ by
+// at most delta
+func assertMatrixInDelta[T matrix.Field](t *testing.T, expected, actual *matrix.Matrix[T], delta float64) {
+	t.Helper()
+	assert.Equal(t, expected.Rows(), actual.Rows())
+	assert.Equal(t, expected.Cols(), actual.Cols())
+	for i := range expected.Rows() {
+		for j := range expected.Cols() {
+			d := complex128(0)
+			switch e := any(expected.Get(i, j)).(type) {
+			case float32:
+				d = complex(float64(e-any(actual.Get(i, j)).(float32)), 0)
+			case float64:
+				d = complex(e-any(actual.Get(i, j)).(float64), 0)
+			case complex64:
+				d = complex128(e - any(actual.Get(i, j)).(complex64))
+			case complex128:
+				d = e - any(actual.Get(i, j)).(complex128)
+			}
+			assert.LessOrEqual(t, cmplx.Abs(d), delta, "element (%d, %d)", i, j)
+		}
+	}
+}
+
+func randomFloatMatrix(n int, seed uint64) *matrix.Matrix[float64] {
+	rng := rand.New(rand.NewPCG(seed, 2))
+	m := matrix.Create[float64](n, n)
+	m.Apply(func(float64) float64 { return rng.NormFloat64() })
+	return m
+}
+
+func TestDecomposeLU(t *testing.T) {
+	m := matrix.CreateFromSlice([][]float64{
+		{1, 2, 3},
+		{4, 5, 6},
+		{7, 8, 10},
+	})
+	d, err := matrix.DecomposeLU(m)
+	assert.Nil(t, err)
+	assert.False(t, d.Singular())
+
+	// The largest element of the first column is the first pivot
+	assert.Equal(t, matrix.CreateFromSlice([][]float64{
+		{0, 0, 1},
+		{1, 0, 0},
+		{0, 1, 0},
+	}), d.P())
+	assertMatrixInDelta(t, matrix.Multiply(d.P(), m), matrix.Multiply(d.L(), d.U()), 1e-12)
+
+	l, u := d.L(), d.U()
+	for i := range 3 {
+		assert.Equal(t, 1.0, l.Get(i, i))
+		for j := i + 1; j < 3; j++ {
+			assert.Zero(t, l.Get(i, j))
+			assert.Zero(t, u.Get(j, i))
+		}
+	}
+
+	for _, n := range []int{1, 10, 50} {
+		r := randomFloatMatrix(n, uint64(n))
+		d, err := matrix.DecomposeLU(r)
+		assert.Nil(t, err)
+		assertMatrixInDelta(t, matrix.Multiply(d.P(), r), matrix.Multiply(d.L(), d.U()), 1e-12)
+	}
+
+	_, err = matrix.DecomposeLU(matrix.Create[float64](2, 3))
+	assert.ErrorIs(t, err, matrix.ErrIncompatibleMatrixDimensions)
+}
+
+func TestDeterminant(t *testing.T) {
+	det, err := matrix.Determinant(matrix.CreateFromSlice([][]float64{
+		{1, 2, 3},
+		{4, 5, 6},
+		{7, 8, 10},
+	}))
+	assert.Nil(t, err)
+	assert.InDelta(t, -3, det, 1e-12)
+
+	det32, err := matrix.Determinant(matrix.CreateFromSlice([][]float32{
+		{0, 2},
+		{3, 4},
+	}))
+	assert.Nil(t, err)
+	assert.Equal(t, float32(-6), det32)
+
+	detC, err := matrix.Determinant(matrix.CreateFromSlice([][]complex128{
+		{1i, 2},
+		{3, 4i},
+	}))
+	assert.Nil(t, err)
+	assert.InDelta(t, 0, cmplx.Abs(detC-(-10)), 1e-12)
+
+	// Singular matrices have a zero determinant
+	det, err = matrix.Determinant(matrix.CreateFromSlice([][]float64{
+		{1, 2},
+		{2, 4},
+	}))
+	assert.Nil(t, err)
+	assert.Zero(t, det)
+
+	det, err = matrix.Determinant(matrix.Identity[float64](20).Scale(2))
+	assert.Nil(t, err)
+	assert.Equal(t, 1048576.0, det)
+
+	_, err = matrix.Determinant(matrix.Create[complex64](3, 1))
+	assert.ErrorIs(t, err, matrix.ErrIncompatibleMatrixDimensions)
+}
+
+func TestInverse(t *testing.T) {
+	inverse, err := matrix.Inverse(matrix.CreateFromSlice([][]float64{
+		{4, 7},
+		{2, 6},
+	}))
+	assert.Nil(t, err)
+	assertMatrixInDelta(t, matrix.CreateFromSlice([][]float64{
+		{0.6, -0.7},
+		{-0.2, 0.4},
+	}), inverse, 1e-12)
+
+	r := randomFloatMatrix(40, 3)
+	inverse, err = matrix.Inverse(r)
+	assert.Nil(t, err)
+	assertMatrixInDelta(t, matrix.Identity[float64](40), matrix.Multiply(r, inverse), 1e-10)
+	assertMatrixInDelta(t, matrix.Identity[float64](40), matrix.Multiply(inverse, r), 1e-10)
+
+	c := matrix.CreateFromSlice([][]complex64{
+		{1 + 1i, 2},
+		{0, 1i},
+	})
+	inverseC, err := matrix.Inverse(c)
+	assert.Nil(t, err)
+	assertMatrixInDelta(t, matrix.Identity[complex64](2), matrix.Multiply(c, inverseC), 1e-6)
+
+	for _, singular := range []*matrix.Matrix[float64]{
+		matrix.Create[float64](3, 3),
+		matrix.CreateFromSlice([][]float64{{1, 2}, {2, 4}}),
+		// Rank deficient up to rounding errors
+		matrix.CreateFromSlice([][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}}),
+	} {
+		_, err = matrix.Inverse(singular)
+		assert.ErrorIs(t, err, matrix.ErrSingular)
+	}
+	_, err = matrix.Inverse(matrix.CreateFromSlice([][]float32{{1, 2}, {0.5, 1}}))
+	assert.ErrorIs(t, err, matrix.ErrSingular)
+	_, err = matrix.Inverse(matrix.Create[float64](2, 3))
+	assert.ErrorIs(t, err, matrix.ErrIncompatibleMatrixDimensions)
+}
+
+func TestSolve(t *testing.T) {
+	a := matrix.CreateFromSlice([][]float64{
+		{2, 1, -1},
+		{-3, -1, 2},
+		{-2, 1, 2},
+	})
+	b := matrix.CreateFromSlice([][]float64{{8}, {-11}, {-3}})
+	x, err := matrix.Solve(a, b)
+	assert.Nil(t, err)
+	assertMatrixInDelta(t, matrix.CreateFromSlice([][]float64{{2}, {3}, {-1}}), x, 1e-12)
+
+	// Several right hand sides with one decomposition
+	r := randomFloatMatrix(30, 4)
+	d, err := matrix.DecomposeLU(r)
+	assert.Nil(t, err)
+	expected := randomFloatMatrix(30, 5).SubMatrix(0, 0, 30, 4).Copy()
+	x, err = d.Solve(matrix.Multiply(r, expected))
+	assert.Nil(t, err)
+	assertMatrixInDelta(t, expected, x, 1e-10)
+
+	// The right hand side may be a view and is not modified
+	bc := b.Copy()
+	x, err = matrix.Solve(a, bc.Col(0))
+	assert.Nil(t, err)
+	assert.Equal(t, b, bc)
+	assertMatrixInDelta(t, matrix.CreateFromSlice([][]float64{{2}, {3}, {-1}}), x, 1e-12)
+
+	ac := matrix.CreateFromSlice([][]complex128{
+		{2, 1i},
+		{-1i, 3},
+	})
+	xc, err := matrix.Solve(ac, matrix.CreateFromSlice([][]complex128{{2 + 1i}, {3 - 1i}}))
+	assert.Nil(t, err)
+	assertMatrixInDelta(t, matrix.CreateFromSlice([][]complex128{{1}, {1}}), xc, 1e-12)
+
+	_, err = matrix.Solve(matrix.CreateFromSlice([][]float64{{1, 1}, {1, 1}}), matrix.Create[float64](2, 1))
+	assert.ErrorIs(t, err, matrix.ErrSingular)
+	_, err = matrix.Solve(a, matrix.Create[float64](2, 1))
+	assert.ErrorIs(t, err, matrix.ErrIncompatibleMatrixDimensions)
+	_, err = matrix.Solve(matrix.Create[float64](2, 3), matrix.Create[float64](2, 1))
+	assert.ErrorIs(t, err, matrix.ErrIncompatibleMatrixDimensions)
+}
@@ -14,6 +14,7 @@ var (
 	ErrInvalidDimensions            = errors.New("Invalid dimensions")
 	ErrIncompatibleMatrixDimensions = errors.New("Incompatible matrix dimensions")
 	ErrIncompatibleDataDimensions   = errors.New("Incompatible data dimensions")
+	ErrSingular                     = errors.New("Matrix is singular")
 )
 
 // Matrices can be created for these underlying types
@@ -25,19 +26,53 @@ type SimpleNumber interface {
 	constraints.Integer | constraints.Float
 }
 
-// Matrix represents a matrix with values of a specific Number type
+// Matrix represents a matrix with values of a specific Number type. The values
+// are stored row by row in a single slice, where consecutive rows start stride
+// elements apart. For matrices created by this package the stride equals the
+// number of columns; views created with SubMatrix, Row and Col share the
+// values of their parent and keep its stride.
 type Matrix[T Number] struct {
 	rows   int
 	cols   int
-	values [][]T
+	stride int
+	values []T
 }
 
-func createValues[T Number](rows, cols int) [][]T {
-	values := make([][]T, rows)
-	for i := range rows {
-		values[i] = make([]T, cols)
+// row returns the values of row i
+func (m *Matrix[T]) row(i int) []T {
+	offset := i * m.stride
+	return m.values[offset : offset+m.cols : offset+m.cols]
+}
+
+// contiguous returns true if the values of the matrix have no gaps between
+// the rows
+func (m *Matrix[T]) contiguous() bool {
+	return m.stride == m.cols
+}
+
+// combine calls fn with the values of m and other, either once for all values
+// or once for every row. The matrices must have the same dimensions.
+func (m *Matrix[T]) combine(other *Matrix[T], fn func(dst, src []T)) {
+	if m.contiguous() && other.contiguous() {
+		n := m.rows * m.cols
+		fn(m.values[:n], other.values[:n])
+		return
+	}
+	for i := range m.rows {
+		fn(m.row(i), other.row(i))
+	}
+}
+
+// update calls fn with the values of m, either once for all values or once for
+// every row
+func (m *Matrix[T]) update(fn func(values []T)) {
+	if m.contiguous() {
+		fn(m.values[:m.rows*m.cols])
+		return
+	}
+	for i := range m.rows {
+		fn(m.row(i))
 	}
-	return values
 }
 
 // Create creates a new matrix with the given number of rows and columns. All
@@ -51,7 +86,8 @@ func Create[T Number](rows, cols int) *Matrix[T] {
 	return &Matrix[T]{
 		rows:   rows,
 		cols:   cols,
-		values: createValues[T](rows, cols),
+		stride: cols,
+		values: make([]T, rows*cols),
 	}
 }
 
@@ -76,7 +112,7 @@ func CreateFromSlice[T Number](values [][]T) *Matrix[T] {
 		if len(values[i]) != cols {
 			panic(ErrIncompatibleDataDimensions)
 		}
-		copy(m.values[i], values[i])
+		copy(m.row(i), values[i])
 	}
 
 	return m
@@ -97,14 +133,7 @@ func CreateFromFlatSlice[T Number](rows, cols int, values []T) *Matrix[T] {
 		panic(ErrIncompatibleDataDimensions)
 	}
 	m := Create[T](rows, cols)
-
-	n := 0
-	for i := range rows {
-		for j := range cols {
-			m.values[i][j] = values[n]
-			n++
-		}
-	}
+	copy(m.values, values)
 	return m
 }
 
@@ -124,8 +153,9 @@ func CreateFromJSON[T Number](data []byte) (*Matrix[T], error) {
 func Convert[U, T SimpleNumber](in *Matrix[T]) *Matrix[U] {
 	out := Create[U](in.rows, in.cols)
 	for i := range in.rows {
-		for j := range in.cols {
-			out.values[i][j] = U(in.values[i][j])
+		dst := out.row(i)
+		for j, v := range in.row(i) {
+			dst[j] = U(v)
 		}
 	}
 	return out
@@ -137,8 +167,9 @@ func Convert[U, T SimpleNumber](in *Matrix[T]) *Matrix[U] {
 func Transform[U, T Number](in *Matrix[T], transformFn func(T) U) *Matrix[U] {
 	out := Create[U](in.rows, in.cols)
 	for i := range in.rows {
-		for j := range in.cols {
-			out.values[i][j] = transformFn(in.values[i][j])
+		dst := out.row(i)
+		for j, v := range in.row(i) {
+			dst[j] = transformFn(v)
 		}
 	}
 	return out
@@ -158,15 +189,52 @@ func HadamardProduct[T Number](first *Matrix[T], additional ...*Matrix[T]) *Matr
 	return first.Copy().HadamardMultiply(additional...)
 }
 
-// Copy creates a deep copy of the matrix and returns the new instance
+// Copy creates a deep copy of the matrix and returns the new instance. The
+// copy of a view doesn't share its values with the parent matrix.
 func (m *Matrix[T]) Copy() *Matrix[T] {
 	mCopy := Create[T](m.rows, m.cols)
-	for i := range m.rows {
-		copy(mCopy.values[i], m.values[i])
-	}
+	mCopy.combine(m, func(dst, src []T) {
+		copy(dst, src)
+	})
 	return mCopy
 }
 
+// SubMatrix returns a view of the rows x cols block of the matrix that starts
+// at the given row and column. The view shares its values with the matrix, so
+// changes to either are visible in both and no values are copied.
+// Operations that take several matrices only handle the receiver being passed
+// as an argument; passing a view that overlaps with the receiver gives
+// undefined results.
+//
+// Panics with ErrInvalidDimensions if rows < 1, cols < 1 or the block doesn't
+// fit in the matrix.
+func (m *Matrix[T]) SubMatrix(row, col, rows, cols int) *Matrix[T] {
+	if rows < 1 || cols < 1 || row < 0 || col < 0 || row+rows > m.rows || col+cols > m.cols {
+		panic(ErrInvalidDimensions)
+	}
+	offset := row*m.stride + col
+	return &Matrix[T]{
+		rows:   rows,
+		cols:   cols,
+		stride: m.stride,
+		values: m.values[offset : offset+(rows-1)*m.stride+cols],
+	}
+}
+
+// Row returns a 1 x cols view of the given row, see SubMatrix.
+//
+// Panics with ErrInvalidDimensions if the row doesn't exist.
+func (m *Matrix[T]) Row(row int) *Matrix[T] {
+	return m.SubMatrix(row, 0, 1, m.cols)
+}
+
+// Col returns a rows x 1 view of the given column, see SubMatrix.
+//
+// Panics with ErrInvalidDimensions if the column doesn't exist.
+func (m *Matrix[T]) Col(col int) *Matrix[T] {
+	return m.SubMatrix(0, col, m.rows, 1)
+}
+
 // Size returns the dimensions of the matrix as (rows, columns)
 func (m *Matrix[T]) Size() (int, int) {
 	return m.rows, m.cols
@@ -185,12 +253,18 @@ func (m *Matrix[T]) Cols() int {
 // Set sets the value of the matrix at the given row and col position to the
 // given value
 func (m *Matrix[T]) Set(row, col int, value T) {
-	m.values[row][col] = value
+	if uint(row) >= uint(m.rows) || uint(col) >= uint(m.cols) {
+		panic("Index out of range")
+	}
+	m.values[row*m.stride+col] = value
 }
 
 // Set assigns the specified value to the element at the given row and column
 func (m *Matrix[T]) Get(row, col int) T {
-	return m.values[row][col]
+	if uint(row) >= uint(m.rows) || uint(col) >= uint(m.cols) {
+		panic("Index out of range")
+	}
+	return m.values[row*m.stride+col]
 }
 
 // Add performs in-place addition of zero or more matrices to this matrix and
@@ -210,22 +284,23 @@ func (m *Matrix[T]) Add(matrices ...*Matrix[T]) *Matrix[T] {
 		}
 	}
 
-	// If we have multiple self references to add, work on duplicate data to
-	// make addition behave as expected
-	values := m.values
+	// If we have multiple self references to add, use a copy of the
+	// original values for them to make addition behave as expected
+	self := m
 	if numSelf > 1 {
-		values = m.Copy().values
+		self = m.Copy()
 	}
 
 	for _, other := range matrices {
-		for i := range m.rows {
-			for j := range m.cols {
-				values[i][j] += other.values[i][j]
-			}
+		if other == m {
+			other = self
 		}
+		m.combine(other, func(dst, src []T) {
+			for j, v := range src {
+				dst[j] += v
+			}
+		})
 	}
-
-	m.values = values
 	return m
 }
 
@@ -246,44 +321,45 @@ func (m *Matrix[T]) Subtract(matrices ...*Matrix[T]) *Matrix[T] {
 		}
 	}
 
-	// If we have multiple self references to subtract, work on duplicate data to
-	// make subtraction behave as expected
-	values := m.values
+	// If we have multiple self references to subtract, use a copy of the
+	// original values for them to make subtraction behave as expected
+	self := m
 	if numSelf > 1 {
-		values = m.Copy().values
+		self = m.Copy()
 	}
 
 	for _, other := range matrices {
-		for i := range m.rows {
-			for j := range m.cols {
-				values[i][j] -= other.values[i][j]
-			}
+		if other == m {
+			other = self
 		}
+		m.combine(other, func(dst, src []T) {
+			for j, v := range src {
+				dst[j] -= v
+			}
+		})
 	}
-
-	m.values = values
 	return m
 }
 
 // Apply performs an in-place transformation of each element of the matrix using
 // the provided function. Returns the receiver.
 func (m *Matrix[T]) Apply(fn func(T) T) *Matrix[T] {
-	for i := range m.rows {
-		for j := range m.cols {
-			m.values[i][j] = fn(m.values[i][j])
+	m.update(func(values []T) {
+		for j, v := range values {
+			values[j] = fn(v)
 		}
-	}
+	})
 	return m
 }
 
 // Scale does an in-place scalar multiplication of the matrix values. Returns
 // the receiver.
 func (m *Matrix[T]) Scale(scalar T) *Matrix[T] {
-	for i := range m.rows {
-		for j := range m.cols {
-			m.values[i][j] *= scalar
+	m.update(func(values []T) {
+		for j := range values {
+			values[j] *= scalar
 		}
-	}
+	})
 	return m
 }
 
@@ -304,32 +380,33 @@ func (m *Matrix[T]) HadamardMultiply(matrices ...*Matrix[T]) *Matrix[T] {
 		}
 	}
 
-	// If we have multiple self references to multiply, work on duplicate data to
-	// make multiplication behave as expected
-	values := m.values
+	// If we have multiple self references to multiply, use a copy of the
+	// original values for them to make multiplication behave as expected
+	self := m
 	if numSelf > 1 {
-		values = m.Copy().values
+		self = m.Copy()
 	}
 
 	for _, other := range matrices {
-		for i := range m.rows {
-			for j := range m.cols {
-				values[i][j] *= other.values[i][j]
-			}
+		if other == m {
+			other = self
 		}
+		m.combine(other, func(dst, src []T) {
+			for j, v := range src {
+				dst[j] *= v
+			}
+		})
 	}
-
-	m.values = values
 	return m
 }
 
 // Fill sets all components of this matrix to the given value. Returns the receiver.
 func (m *Matrix[T]) Fill(value T) *Matrix[T] {
-	for i := range m.rows {
-		for j := range m.cols {
-			m.values[i][j] = value
+	m.update(func(values []T) {
+		for j := range values {
+			values[j] = value
 		}
-	}
+	})
 	return m
 }
 
@@ -358,7 +435,11 @@ func (m *Matrix[T]) UnmarshalJSON(data []byte) error {
 
 	m.rows = rows
 	m.cols = cols
-	m.values = values
+	m.stride = cols
+	m.values = make([]T, rows*cols)
+	for i := range rows {
+		copy(m.row(i), values[i])
+	}
 
 	return nil
 }
@@ -366,5 +447,9 @@ func (m *Matrix[T]) UnmarshalJSON(data []byte) error {
 // MarshalJSON implements json.Marshaler for Matrix, serializing the matrix as
 // a JSON two-dimensional array.
 func (m *Matrix[T]) MarshalJSON() ([]byte, error) {
-	return json.Marshal(m.values)
+	values := make([][]T, m.rows)
+	for i := range m.rows {
+		values[i] = m.row(i)
+	}
+	return json.Marshal(values)
 }
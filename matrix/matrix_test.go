@@ -653,3 +653,132 @@ func TestMatrix_MarshallJSON(t *testing.T) {
 	expected = `[[[1,2],[3,4]],[[5,6,7]]]`
 	assert.Equal(t, expected, string(data))
 }
+
+func TestMatrix_SubMatrix(t *testing.T) {
+	m := matrix.CreateFromSlice([][]int{
+		{1, 2, 3, 4},
+		{5, 6, 7, 8},
+		{9, 10, 11, 12},
+	})
+
+	s := m.SubMatrix(1, 1, 2, 2)
+	rows, cols := s.Size()
+	assert.Equal(t, 2, rows)
+	assert.Equal(t, 2, cols)
+	assert.Equal(t, 6, s.Get(0, 0))
+	assert.Equal(t, 7, s.Get(0, 1))
+	assert.Equal(t, 10, s.Get(1, 0))
+	assert.Equal(t, 11, s.Get(1, 1))
+
+	// The view shares its values with the matrix
+	s.Set(1, 1, 100)
+	assert.Equal(t, 100, m.Get(2, 2))
+	m.Set(1, 2, 70)
+	assert.Equal(t, 70, s.Get(0, 1))
+
+	// Views of views
+	v := s.SubMatrix(1, 0, 1, 2)
+	assert.Equal(t, 10, v.Get(0, 0))
+	assert.Equal(t, 100, v.Get(0, 1))
+
+	// Elements outside the view are out of range
+	assert.PanicsWithValue(t, "Index out of range", func() {
+		s.Get(0, 2)
+	})
+	assert.PanicsWithValue(t, "Index out of range", func() {
+		s.Set(2, 0, 1)
+	})
+
+	for _, args := range [][4]int{
+		{0, 0, 0, 1},
+		{0, 0, 1, 0},
+		{-1, 0, 1, 1},
+		{0, -1, 1, 1},
+		{2, 0, 2, 1},
+		{0, 3, 1, 2},
+	} {
+		assert.PanicsWithValue(t, matrix.ErrInvalidDimensions, func() {
+			m.SubMatrix(args[0], args[1], args[2], args[3])
+		}, "%v", args)
+	}
+}
+
+func TestMatrix_RowCol(t *testing.T) {
+	m := matrix.CreateFromSlice([][]int{
+		{1, 2, 3},
+		{4, 5, 6},
+	})
+
+	row := m.Row(1)
+	assert.Equal(t, 1, row.Rows())
+	assert.Equal(t, 3, row.Cols())
+	assert.Equal(t, matrix.CreateFromSlice([][]int{{4, 5, 6}}), row.Copy())
+
+	col := m.Col(2)
+	assert.Equal(t, 2, col.Rows())
+	assert.Equal(t, 1, col.Cols())
+	assert.Equal(t, matrix.CreateFromSlice([][]int{{3}, {6}}), col.Copy())
+
+	// Operations on views change the parent
+	col.Scale(10)
+	row.Add(matrix.CreateFromSlice([][]int{{1, 1, 1}}))
+	assert.Equal(t, matrix.CreateFromSlice([][]int{
+		{1, 2, 30},
+		{5, 6, 61},
+	}), m)
+
+	assert.PanicsWithValue(t, matrix.ErrInvalidDimensions, func() {
+		m.Row(2)
+	})
+	assert.PanicsWithValue(t, matrix.ErrInvalidDimensions, func() {
+		m.Col(-1)
+	})
+}
+
+func TestMatrix_ViewOperations(t *testing.T) {
+	m := matrix.CreateFromFlatSlice(4, 4, []int{
+		1, 2, 3, 4,
+		5, 6, 7, 8,
+		9, 10, 11, 12,
+		13, 14, 15, 16,
+	})
+	left := m.SubMatrix(0, 0, 4, 2)
+	right := m.SubMatrix(0, 2, 4, 2)
+
+	// Element-wise operations between views of the same matrix
+	left.Add(right, right)
+	right.HadamardMultiply(right)
+	left.Subtract(matrix.Create[int](4, 2).Fill(1))
+	assert.Equal(t, matrix.CreateFromFlatSlice(4, 4, []int{
+		6, 9, 9, 16,
+		18, 21, 49, 64,
+		30, 33, 121, 144,
+		42, 45, 225, 256,
+	}), m)
+
+	// Self references on a view
+	top := m.SubMatrix(0, 1, 1, 2)
+	top.Add(top, top)
+	assert.Equal(t, []int{6, 27, 27, 16}, []int{m.Get(0, 0), m.Get(0, 1), m.Get(0, 2), m.Get(0, 3)})
+
+	// Operations that only touch the view
+	inner := m.SubMatrix(1, 1, 2, 2)
+	inner.Fill(0).Apply(func(v int) int { return v + 1 })
+	assert.Equal(t, matrix.CreateFromFlatSlice(4, 4, []int{
+		6, 27, 27, 16,
+		18, 1, 1, 64,
+		30, 1, 1, 144,
+		42, 45, 225, 256,
+	}), m)
+
+	// Functions that create new matrices
+	assert.Equal(t, matrix.CreateFromSlice([][]int{{18, 30}, {1, 1}, {1, 1}}), m.SubMatrix(1, 0, 2, 3).Transpose())
+	assert.Equal(t, matrix.CreateFromSlice([][]float64{{1, 1}, {1, 1}}), matrix.Convert[float64](inner))
+	assert.Equal(t, matrix.CreateFromSlice([][]int{{2, 2}, {2, 2}}), matrix.Sum(inner, inner))
+	assert.Equal(t, matrix.CreateFromSlice([][]int{{2, 2}, {2, 2}}), matrix.Multiply(inner, inner))
+	assert.Equal(t, 2, inner.Trace())
+
+	data, err := json.Marshal(m.SubMatrix(2, 1, 2, 3))
+	assert.Nil(t, err)
+	assert.Equal(t, `[[1,1,144],[45,225,256]]`, string(data))
+}
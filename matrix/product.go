@@ -0,0 +1,99 @@
+package matrix
+
+import "errors"
+
+var ErrNegativeExponent = errors.New("Negative exponent")
+
+// transposeBlockSize is the size of the square tiles used by Transpose, small
+// enough that a tile of the source and the destination both fit in the cache
+const transposeBlockSize = 32
+
+// Identity creates a new n x n identity matrix.
+//
+// Panics with ErrInvalidDimensions if n < 1.
+func Identity[T Number](n int) *Matrix[T] {
+	m := Create[T](n, n)
+	for i := range n {
+		m.values[i*m.stride+i] = 1
+	}
+	return m
+}
+
+// Multiply computes the matrix product a * b and returns it as a new matrix.
+// The rows of b are traversed sequentially, which keeps the computation cache
+// friendly for large matrices.
+//
+// Panics with ErrIncompatibleMatrixDimensions if the number of columns of a
+// doesn't match the number of rows of b.
+func Multiply[T Number](a, b *Matrix[T]) *Matrix[T] {
+	if a.cols != b.rows {
+		panic(ErrIncompatibleMatrixDimensions)
+	}
+
+	out := Create[T](a.rows, b.cols)
+	for i := range a.rows {
+		row := out.row(i)
+		for k, aik := range a.row(i) {
+			for j, bkj := range b.row(k) {
+				row[j] += aik * bkj
+			}
+		}
+	}
+	return out
+}
+
+// Power computes m^exponent with exponentiation by squaring and returns it as
+// a new matrix. The zeroth power is the identity matrix.
+//
+// Panics with ErrIncompatibleMatrixDimensions if m is not square.
+// Panics with ErrNegativeExponent if exponent < 0.
+func Power[T Number](m *Matrix[T], exponent int) *Matrix[T] {
+	if m.rows != m.cols {
+		panic(ErrIncompatibleMatrixDimensions)
+	}
+	if exponent < 0 {
+		panic(ErrNegativeExponent)
+	}
+
+	result := Identity[T](m.rows)
+	base := m
+	for ; exponent > 0; exponent >>= 1 {
+		if exponent&1 == 1 {
+			result = Multiply(result, base)
+		}
+		if exponent > 1 {
+			base = Multiply(base, base)
+		}
+	}
+	return result
+}
+
+// Transpose creates the transpose of the matrix and returns the new instance
+func (m *Matrix[T]) Transpose() *Matrix[T] {
+	out := Create[T](m.cols, m.rows)
+	for i0 := 0; i0 < m.rows; i0 += transposeBlockSize {
+		for j0 := 0; j0 < m.cols; j0 += transposeBlockSize {
+			for i := i0; i < min(i0+transposeBlockSize, m.rows); i++ {
+				row := m.row(i)
+				for j := j0; j < min(j0+transposeBlockSize, m.cols); j++ {
+					out.values[j*out.stride+i] = row[j]
+				}
+			}
+		}
+	}
+	return out
+}
+
+// Trace returns the sum of the diagonal elements of the matrix.
+//
+// Panics with ErrIncompatibleMatrixDimensions if the matrix is not square.
+func (m *Matrix[T]) Trace() T {
+	if m.rows != m.cols {
+		panic(ErrIncompatibleMatrixDimensions)
+	}
+	var trace T
+	for i := range m.rows {
+		trace += m.values[i*m.stride+i]
+	}
+	return trace
+}
@@ -0,0 +1,182 @@
+package matrix_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+// naiveMultiply is the textbook definition of the matrix product
+func naiveMultiply[T matrix.Number](a, b *matrix.Matrix[T]) *matrix.Matrix[T] {
+	out := matrix.Create[T](a.Rows(), b.Cols())
+	for i := range a.Rows() {
+		for j := range b.Cols() {
+			var sum T
+			for k := range a.Cols() {
+				sum += a.Get(i, k) * b.Get(k, j)
+			}
+			out.Set(i, j, sum)
+		}
+	}
+	return out
+}
+
+func randomMatrix(rows, cols int, seed uint64) *matrix.Matrix[int] {
+	rng := rand.New(rand.NewPCG(seed, 0))
+	m := matrix.Create[int](rows, cols)
+	for i := range rows {
+		for j := range cols {
+			m.Set(i, j, rng.IntN(21)-10)
+		}
+	}
+	return m
+}
+
+func TestIdentity(t *testing.T) {
+	m := matrix.Identity[float64](3)
+	assert.Equal(t, matrix.CreateFromSlice([][]float64{
+		{1, 0, 0},
+		{0, 1, 0},
+		{0, 0, 1},
+	}), m)
+
+	assert.PanicsWithValue(t, matrix.ErrInvalidDimensions, func() {
+		matrix.Identity[int](0)
+	})
+}
+
+func TestMultiply(t *testing.T) {
+	a := matrix.CreateFromSlice([][]int{
+		{1, 2, 3},
+		{4, 5, 6},
+	})
+	b := matrix.CreateFromSlice([][]int{
+		{7, 8},
+		{9, 10},
+		{11, 12},
+	})
+	assert.Equal(t, matrix.CreateFromSlice([][]int{
+		{58, 64},
+		{139, 154},
+	}), matrix.Multiply(a, b))
+	assert.Equal(t, matrix.CreateFromSlice([][]int{
+		{39, 54, 69},
+		{49, 68, 87},
+		{59, 82, 105},
+	}), matrix.Multiply(b, a))
+
+	// The arguments are not modified and may be the same matrix
+	assert.Equal(t, matrix.CreateFromSlice([][]int{{1, 2, 3}, {4, 5, 6}}), a)
+	sq := matrix.CreateFromSlice([][]int{{1, 1}, {1, 0}})
+	assert.Equal(t, matrix.CreateFromSlice([][]int{{2, 1}, {1, 1}}), matrix.Multiply(sq, sq))
+
+	for _, size := range [][3]int{{1, 1, 1}, {5, 7, 3}, {33, 65, 17}} {
+		a := randomMatrix(size[0], size[1], 1)
+		b := randomMatrix(size[1], size[2], 2)
+		assert.Equal(t, naiveMultiply(a, b), matrix.Multiply(a, b))
+	}
+
+	c := matrix.CreateFromSlice([][]complex128{{1i, 1}, {0, 2}})
+	assert.Equal(t, matrix.CreateFromSlice([][]complex128{{-1, 1i + 2}, {0, 4}}), matrix.Multiply(c, c))
+
+	assert.PanicsWithValue(t, matrix.ErrIncompatibleMatrixDimensions, func() {
+		matrix.Multiply(a, a)
+	})
+}
+
+func TestPower(t *testing.T) {
+	// Fibonacci numbers
+	m := matrix.CreateFromSlice([][]int{{1, 1}, {1, 0}})
+	assert.Equal(t, matrix.Identity[int](2), matrix.Power(m, 0))
+	assert.Equal(t, m, matrix.Power(m, 1))
+	assert.Equal(t, matrix.CreateFromSlice([][]int{{89, 55}, {55, 34}}), matrix.Power(m, 10))
+	assert.Equal(t, 12586269025, matrix.Power(m, 50).Get(0, 1))
+	assert.Equal(t, matrix.CreateFromSlice([][]int{{1, 1}, {1, 0}}), m)
+
+	// A transition matrix of a Markov chain converges to its stationary
+	// distribution
+	p := matrix.CreateFromSlice([][]float64{
+		{0.9, 0.1},
+		{0.5, 0.5},
+	})
+	limit := matrix.Power(p, 64)
+	for i := range 2 {
+		assert.InDelta(t, 5.0/6, limit.Get(i, 0), 1e-12)
+		assert.InDelta(t, 1.0/6, limit.Get(i, 1), 1e-12)
+	}
+
+	r := randomMatrix(6, 6, 3)
+	expected := matrix.Identity[int](6)
+	for range 7 {
+		expected = naiveMultiply(expected, r)
+	}
+	assert.Equal(t, expected, matrix.Power(r, 7))
+
+	assert.PanicsWithValue(t, matrix.ErrIncompatibleMatrixDimensions, func() {
+		matrix.Power(matrix.Create[int](2, 3), 2)
+	})
+	assert.PanicsWithValue(t, matrix.ErrNegativeExponent, func() {
+		matrix.Power(m, -1)
+	})
+}
+
+func TestMatrix_Transpose(t *testing.T) {
+	m := matrix.CreateFromSlice([][]int{
+		{1, 2, 3},
+		{4, 5, 6},
+	})
+	assert.Equal(t, matrix.CreateFromSlice([][]int{
+		{1, 4},
+		{2, 5},
+		{3, 6},
+	}), m.Transpose())
+
+	// Larger than a single tile
+	r := randomMatrix(70, 45, 4)
+	tr := r.Transpose()
+	assert.Equal(t, 45, tr.Rows())
+	assert.Equal(t, 70, tr.Cols())
+	for i := range 70 {
+		for j := range 45 {
+			assert.Equal(t, r.Get(i, j), tr.Get(j, i))
+		}
+	}
+	assert.Equal(t, r, tr.Transpose())
+}
+
+func TestMatrix_Trace(t *testing.T) {
+	m := matrix.CreateFromSlice([][]int{
+		{1, 2, 3},
+		{4, 5, 6},
+		{7, 8, 9},
+	})
+	assert.Equal(t, 15, m.Trace())
+	assert.Equal(t, 5.0, matrix.Identity[float64](5).Trace())
+
+	assert.PanicsWithValue(t, matrix.ErrIncompatibleMatrixDimensions, func() {
+		matrix.Create[int](2, 3).Trace()
+	})
+}
+
+func BenchmarkMultiply256(b *testing.B) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	m := matrix.Create[float64](256, 256)
+	for i := range 256 {
+		for j := range 256 {
+			m.Set(i, j, rng.Float64())
+		}
+	}
+	b.ResetTimer()
+	for range b.N {
+		matrix.Multiply(m, m)
+	}
+}
+
+func BenchmarkTranspose256(b *testing.B) {
+	m := matrix.Create[float64](256, 256)
+	for range b.N {
+		m.Transpose()
+	}
+}
@@ -0,0 +1,219 @@
+package matrix
+
+import (
+	"errors"
+	"math"
+
+	"git.omicron.one/playground/cryptography/internal/special"
+)
+
+var ErrInvalidDistribution = errors.New("Values don't form a distribution")
+
+// ChiSquaredTest holds the outcome of a chi-squared test. PValue is the
+// probability of a statistic at least this large if the null hypothesis
+// holds.
+type ChiSquaredTest struct {
+	Statistic        float64 `json:"statistic"`
+	DegreesOfFreedom int     `json:"degrees_of_freedom"`
+	PValue           float64 `json:"p_value"`
+}
+
+// newChiSquaredTest computes the p-value of a chi-squared statistic. Without
+// degrees of freedom the distribution is concentrated at zero.
+func newChiSquaredTest(statistic float64, df int) ChiSquaredTest {
+	test := ChiSquaredTest{
+		Statistic:        statistic,
+		DegreesOfFreedom: df,
+		PValue:           1,
+	}
+	if df > 0 {
+		test.PValue = special.Igamc(float64(df)/2, statistic/2)
+	} else if statistic > 0 {
+		test.PValue = 0
+	}
+	return test
+}
+
+// Total returns the sum of all values of the matrix. The sum is computed in
+// T, so it overflows if it doesn't fit in T.
+func (m *Matrix[T]) Total() T {
+	var total T
+	for i := range m.rows {
+		for _, v := range m.row(i) {
+			total += v
+		}
+	}
+	return total
+}
+
+// RowSums returns a rows x 1 matrix with the sum of every row
+func (m *Matrix[T]) RowSums() *Matrix[T] {
+	sums := Create[T](m.rows, 1)
+	for i := range m.rows {
+		for _, v := range m.row(i) {
+			sums.values[i] += v
+		}
+	}
+	return sums
+}
+
+// ColSums returns a 1 x cols matrix with the sum of every column
+func (m *Matrix[T]) ColSums() *Matrix[T] {
+	sums := Create[T](1, m.cols)
+	for i := range m.rows {
+		for j, v := range m.row(i) {
+			sums.values[j] += v
+		}
+	}
+	return sums
+}
+
+// Min returns the smallest value of the matrix and its position. If the value
+// occurs more than once the first position in row-major order is returned.
+func Min[T SimpleNumber](m *Matrix[T]) (value T, row, col int) {
+	value = m.values[0]
+	for i := range m.rows {
+		for j, v := range m.row(i) {
+			if v < value {
+				value, row, col = v, i, j
+			}
+		}
+	}
+	return value, row, col
+}
+
+// Max returns the largest value of the matrix and its position. If the value
+// occurs more than once the first position in row-major order is returned.
+func Max[T SimpleNumber](m *Matrix[T]) (value T, row, col int) {
+	value = m.values[0]
+	for i := range m.rows {
+		for j, v := range m.row(i) {
+			if v > value {
+				value, row, col = v, i, j
+			}
+		}
+	}
+	return value, row, col
+}
+
+// Mean returns the mean of all values of the matrix
+func Mean[T SimpleNumber](m *Matrix[T]) float64 {
+	sum := 0.0
+	for i := range m.rows {
+		for _, v := range m.row(i) {
+			sum += float64(v)
+		}
+	}
+	return sum / float64(m.rows*m.cols)
+}
+
+// Variance returns the population variance of all values of the matrix
+func Variance[T SimpleNumber](m *Matrix[T]) float64 {
+	mean := Mean(m)
+	sum := 0.0
+	for i := range m.rows {
+		for _, v := range m.row(i) {
+			d := float64(v) - mean
+			sum += d * d
+		}
+	}
+	return sum / float64(m.rows*m.cols)
+}
+
+// StdDev returns the population standard deviation of all values of the
+// matrix
+func StdDev[T SimpleNumber](m *Matrix[T]) float64 {
+	return math.Sqrt(Variance(m))
+}
+
+// Normalize returns a new matrix with the values of m divided by their total,
+// so that they form a probability distribution.
+// Returns ErrInvalidDistribution if any value is negative or all values are
+// zero.
+func Normalize[T SimpleNumber](m *Matrix[T]) (*Matrix[float64], error) {
+	p, _, err := normalize(m)
+	return p, err
+}
+
+// normalize implements Normalize and also returns the total. The total is
+// summed as float64, so unlike Total it can't overflow for small types.
+func normalize[T SimpleNumber](m *Matrix[T]) (*Matrix[float64], float64, error) {
+	total := 0.0
+	for i := range m.rows {
+		for _, v := range m.row(i) {
+			if v < 0 {
+				return nil, 0, ErrInvalidDistribution
+			}
+			total += float64(v)
+		}
+	}
+	if total == 0 {
+		return nil, 0, ErrInvalidDistribution
+	}
+
+	out := Convert[float64](m)
+	return out.Scale(1 / total), total, nil
+}
+
+// ChiSquared performs Pearson's chi-squared goodness of fit test of the
+// observed counts against the expected counts. Values that are zero in both
+// matrices are ignored; a non-zero observation of an expected zero gives an
+// infinite statistic. The test has one degree of freedom less than the number
+// of values that aren't ignored.
+//
+// Panics with ErrIncompatibleMatrixDimensions if the matrices don't have
+// matching dimensions.
+func ChiSquared[T, U SimpleNumber](observed *Matrix[T], expected *Matrix[U]) ChiSquaredTest {
+	if observed.rows != expected.rows || observed.cols != expected.cols {
+		panic(ErrIncompatibleMatrixDimensions)
+	}
+
+	statistic := 0.0
+	cells := 0
+	for i := range observed.rows {
+		e := expected.row(i)
+		for j, o := range observed.row(i) {
+			if o == 0 && e[j] == 0 {
+				continue
+			}
+			cells++
+			d := float64(o) - float64(e[j])
+			if d != 0 {
+				statistic += d * d / float64(e[j])
+			}
+		}
+	}
+	return newChiSquaredTest(statistic, max(cells-1, 0))
+}
+
+// ChiSquaredIndependence performs Pearson's chi-squared test of independence
+// of the rows and columns of a contingency table of counts, with
+// (rows - 1) * (cols - 1) degrees of freedom. The expected counts follow from
+// the row and column sums.
+// Returns ErrInvalidDistribution if any value is negative or all values are
+// zero.
+func ChiSquaredIndependence[T SimpleNumber](observed *Matrix[T]) (ChiSquaredTest, error) {
+	p, total, err := normalize(observed)
+	if err != nil {
+		return ChiSquaredTest{}, err
+	}
+
+	rows, cols := p.RowSums(), p.ColSums()
+	expected := Multiply(rows, cols).Scale(total)
+
+	// Empty rows and columns don't contribute and have no degrees of freedom
+	nonEmptyRows, nonEmptyCols := 0, 0
+	for _, v := range rows.values {
+		if v > 0 {
+			nonEmptyRows++
+		}
+	}
+	for _, v := range cols.values {
+		if v > 0 {
+			nonEmptyCols++
+		}
+	}
+
+	test := ChiSquared(observed, expected)
+	return newChiSquaredTest(test.Statistic, (nonEmptyRows-1)*(nonEmptyCols-1)), nil
+}
@@ -0,0 +1,173 @@
+package matrix_test
+
+import (
+	"math"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/matrix"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatrix_Sums(t *testing.T) {
+	m := matrix.CreateFromSlice([][]int{
+		{1, 2, 3},
+		{4, 5, 6},
+	})
+	assert.Equal(t, 21, m.Total())
+	assert.Equal(t, matrix.CreateFromSlice([][]int{{6}, {15}}), m.RowSums())
+	assert.Equal(t, matrix.CreateFromSlice([][]int{{5, 7, 9}}), m.ColSums())
+
+	// Views only sum their own values
+	v := m.SubMatrix(0, 1, 2, 2)
+	assert.Equal(t, 16, v.Total())
+	assert.Equal(t, matrix.CreateFromSlice([][]int{{5}, {11}}), v.RowSums())
+	assert.Equal(t, matrix.CreateFromSlice([][]int{{7, 9}}), v.ColSums())
+
+	c := matrix.CreateFromSlice([][]complex128{{1i, 1}, {2, 1i}})
+	assert.Equal(t, 3+2i, c.Total())
+}
+
+func TestMinMax(t *testing.T) {
+	m := matrix.CreateFromSlice([][]float64{
+		{3, -1, 7},
+		{7, 0, -1},
+	})
+	value, row, col := matrix.Min(m)
+	assert.Equal(t, -1.0, value)
+	assert.Equal(t, 0, row)
+	assert.Equal(t, 1, col)
+
+	value, row, col = matrix.Max(m)
+	assert.Equal(t, 7.0, value)
+	assert.Equal(t, 0, row)
+	assert.Equal(t, 2, col)
+
+	value, row, col = matrix.Max(m.SubMatrix(1, 1, 1, 2))
+	assert.Equal(t, 0.0, value)
+	assert.Equal(t, 0, row)
+	assert.Equal(t, 0, col)
+
+	u, row, col := matrix.Min(matrix.CreateFromSlice([][]uint8{{5}, {2}, {9}}))
+	assert.Equal(t, uint8(2), u)
+	assert.Equal(t, 1, row)
+	assert.Equal(t, 0, col)
+}
+
+func TestMeanVariance(t *testing.T) {
+	m := matrix.CreateFromSlice([][]int{
+		{2, 4, 4, 4},
+		{5, 5, 7, 9},
+	})
+	assert.Equal(t, 5.0, matrix.Mean(m))
+	assert.Equal(t, 4.0, matrix.Variance(m))
+	assert.Equal(t, 2.0, matrix.StdDev(m))
+
+	// No overflow for large integer values
+	big := matrix.CreateFromSlice([][]uint8{{200, 250}})
+	assert.Equal(t, 225.0, matrix.Mean(big))
+	assert.Equal(t, 625.0, matrix.Variance(big))
+
+	assert.Equal(t, 0.0, matrix.Variance(matrix.Create[float32](3, 3).Fill(1.5)))
+}
+
+func TestNormalize(t *testing.T) {
+	p, err := matrix.Normalize(matrix.CreateFromSlice([][]int{
+		{1, 3},
+		{0, 4},
+	}))
+	assert.Nil(t, err)
+	assert.Equal(t, matrix.CreateFromSlice([][]float64{
+		{0.125, 0.375},
+		{0, 0.5},
+	}), p)
+	assert.Equal(t, 1.0, p.Total())
+
+	_, err = matrix.Normalize(matrix.Create[int](2, 2))
+	assert.ErrorIs(t, err, matrix.ErrInvalidDistribution)
+	_, err = matrix.Normalize(matrix.CreateFromSlice([][]float64{{1, -0.5}}))
+	assert.ErrorIs(t, err, matrix.ErrInvalidDistribution)
+}
+
+func TestChiSquared(t *testing.T) {
+	// A die rolled 60 times
+	observed := matrix.CreateFromSlice([][]int{{5, 8, 9, 8, 10, 20}})
+	expected := matrix.Create[float64](1, 6).Fill(10)
+	test := matrix.ChiSquared(observed, expected)
+	assert.InDelta(t, 13.4, test.Statistic, 1e-12)
+	assert.Equal(t, 5, test.DegreesOfFreedom)
+	assert.InDelta(t, 0.019905, test.PValue, 1e-6)
+
+	// Cells that are empty in both matrices have no degrees of freedom
+	paddedObserved := matrix.Create[int](2, 4)
+	paddedObserved.SubMatrix(0, 0, 2, 3).Add(matrix.CreateFromFlatSlice(2, 3, []int{5, 8, 9, 8, 10, 20}))
+	paddedExpected := matrix.Create[float64](2, 4)
+	paddedExpected.SubMatrix(0, 0, 2, 3).Fill(10)
+	test = matrix.ChiSquared(paddedObserved, paddedExpected)
+	assert.InDelta(t, 13.4, test.Statistic, 1e-12)
+	assert.Equal(t, 5, test.DegreesOfFreedom)
+	assert.InDelta(t, 0.019905, test.PValue, 1e-6)
+
+	// A single cell has no degrees of freedom at all
+	test = matrix.ChiSquared(matrix.CreateFromSlice([][]int{{3, 0}}), matrix.CreateFromSlice([][]int{{3, 0}}))
+	assert.Equal(t, 0, test.DegreesOfFreedom)
+	assert.Equal(t, 1.0, test.PValue)
+	test = matrix.ChiSquared(matrix.CreateFromSlice([][]int{{4, 0}}), matrix.CreateFromSlice([][]int{{3, 0}}))
+	assert.Equal(t, 0, test.DegreesOfFreedom)
+	assert.Zero(t, test.PValue)
+
+	// A perfect fit
+	test = matrix.ChiSquared(expected, expected)
+	assert.Zero(t, test.Statistic)
+	assert.Equal(t, 1.0, test.PValue)
+
+	// Impossible observations
+	test = matrix.ChiSquared(
+		matrix.CreateFromSlice([][]int{{1, 0, 2}}),
+		matrix.CreateFromSlice([][]int{{0, 0, 3}}),
+	)
+	assert.True(t, math.IsInf(test.Statistic, 1))
+	assert.Zero(t, test.PValue)
+
+	assert.PanicsWithValue(t, matrix.ErrIncompatibleMatrixDimensions, func() {
+		matrix.ChiSquared(observed, matrix.Create[float64](6, 1))
+	})
+}
+
+func TestChiSquaredIndependence(t *testing.T) {
+	observed := matrix.CreateFromSlice([][]int{
+		{90, 60, 104, 95},
+		{30, 50, 51, 20},
+		{30, 40, 45, 35},
+	})
+	test, err := matrix.ChiSquaredIndependence(observed)
+	assert.Nil(t, err)
+	assert.InDelta(t, 24.5712, test.Statistic, 1e-4)
+	assert.Equal(t, 6, test.DegreesOfFreedom)
+	assert.InDelta(t, 0.000410, test.PValue, 1e-6)
+
+	// Empty rows and columns are ignored
+	padded := matrix.Create[int](4, 5)
+	padded.SubMatrix(0, 0, 3, 4).Add(observed)
+	test, err = matrix.ChiSquaredIndependence(padded)
+	assert.Nil(t, err)
+	assert.InDelta(t, 24.5712, test.Statistic, 1e-4)
+	assert.Equal(t, 6, test.DegreesOfFreedom)
+
+	// The total of 650 doesn't fit in the counts
+	test, err = matrix.ChiSquaredIndependence(matrix.Convert[uint8](observed))
+	assert.Nil(t, err)
+	assert.InDelta(t, 24.5712, test.Statistic, 1e-4)
+	assert.Equal(t, 6, test.DegreesOfFreedom)
+	assert.InDelta(t, 0.000410, test.PValue, 1e-6)
+
+	// Exactly independent
+	test, err = matrix.ChiSquaredIndependence(matrix.CreateFromSlice([][]int{
+		{1, 2},
+		{2, 4},
+	}))
+	assert.Nil(t, err)
+	assert.InDelta(t, 0, test.Statistic, 1e-12)
+
+	_, err = matrix.ChiSquaredIndependence(matrix.Create[int](2, 2))
+	assert.ErrorIs(t, err, matrix.ErrInvalidDistribution)
+}
@@ -0,0 +1,57 @@
+package padding
+
+import "crypto/subtle"
+
+type iso7816 struct {
+	blockSize int
+}
+
+// NewISO7816 returns the ISO/IEC 7816-4 padding scheme for the given block
+// size, also known as bit padding. The padding consists of a single 0x80 byte
+// followed by zero bytes. At least one byte of padding is always added.
+//
+// Panics with ErrInvalidBlockSize if blockSize < 1 or blockSize > 255.
+func NewISO7816(blockSize int) Padding {
+	checkBlockSize(blockSize)
+	return &iso7816{blockSize: blockSize}
+}
+
+func (p *iso7816) BlockSize() int {
+	return p.blockSize
+}
+
+func (p *iso7816) Pad(data []byte) []byte {
+	padLen := p.blockSize - len(data)%p.blockSize
+	out := pad(data, padLen)
+	out[len(data)] = 0x80
+	return out
+}
+
+func (p *iso7816) Unpad(data []byte) ([]byte, error) {
+	if err := checkLength(data, p.blockSize); err != nil {
+		return nil, err
+	}
+
+	n := len(data)
+	found := 0
+	invalid := 0
+	padLen := 0
+
+	// Search the last block for the 0x80 marker from the end. Everything
+	// after the marker must be zero. The whole block is always inspected.
+	for i := 1; i <= p.blockSize; i++ {
+		b := data[n-i]
+		isZero := subtle.ConstantTimeByteEq(b, 0)
+		isMarker := subtle.ConstantTimeByteEq(b, 0x80)
+		notFound := found ^ 1
+
+		invalid |= notFound & ((isZero | isMarker) ^ 1)
+		padLen = subtle.ConstantTimeSelect(notFound&isMarker, i, padLen)
+		found |= isMarker
+	}
+
+	if found&(invalid^1) != 1 {
+		return nil, ErrInvalidPadding
+	}
+	return data[:n-padLen], nil
+}
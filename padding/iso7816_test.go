@@ -0,0 +1,44 @@
+package padding_test
+
+import (
+	"testing"
+
+	"git.omicron.one/playground/cryptography/padding"
+	. "git.omicron.one/playground/cryptography/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestISO7816(t *testing.T) {
+	p := padding.NewISO7816(8)
+	assert.Equal(t, 8, p.BlockSize())
+
+	assert.Equal(t, DeHex("8000000000000000"), p.Pad(nil))
+	assert.Equal(t, DeHex("aabbcc8000000000"), p.Pad(DeHex("aabbcc")))
+	assert.Equal(t, DeHex("aabbccddeeff0080"), p.Pad(DeHex("aabbccddeeff00")))
+	assert.Equal(t, DeHex("aabbccddeeff0001"+"8000000000000000"), p.Pad(DeHex("aabbccddeeff0001")))
+
+	// Bytes before the marker may have any value, including 0x80
+	unpadded, err := p.Unpad(DeHex("8080800080000000"))
+	assert.Nil(t, err)
+	assert.Equal(t, DeHex("80808000"), unpadded)
+
+	for _, bs := range []int{1, 8, 16, 255} {
+		testRoundTrip(t, padding.NewISO7816(bs))
+		testInvalidLength(t, padding.NewISO7816(bs))
+	}
+}
+
+func TestISO7816Invalid(t *testing.T) {
+	p := padding.NewISO7816(8)
+	invalid := []string{
+		"0000000000000000", // no marker
+		"aabbccddeeff0001", // no marker
+		"aabbcc8000010000", // non-zero byte after the marker
+		"aabbccddeeff8001",
+	}
+	for _, data := range invalid {
+		unpadded, err := p.Unpad(DeHex(data))
+		assert.ErrorIs(t, err, padding.ErrInvalidPadding, data)
+		assert.Nil(t, unpadded)
+	}
+}
@@ -0,0 +1,51 @@
+// Package padding implements padding schemes that extend messages to a
+// multiple of the block size of a block cipher. Unpadding runs in constant time
+// with respect to the contents of the last block, so that it can't be used as a
+// padding oracle.
+package padding
+
+import "errors"
+
+var (
+	ErrInvalidBlockSize = errors.New("Invalid block size")
+	ErrInvalidLength    = errors.New("Input length is not a multiple of the block size")
+	ErrInvalidPadding   = errors.New("Invalid padding")
+)
+
+// A Padding pads and unpads data for a fixed block size.
+type Padding interface {
+	// Pad returns a new slice with the data followed by the padding. The
+	// length of the result is a multiple of the block size.
+	Pad(data []byte) []byte
+	// Unpad removes the padding and returns the data as a subslice of the
+	// input. Returns ErrInvalidLength if the length of the input isn't a
+	// positive multiple of the block size and ErrInvalidPadding if the
+	// padding is malformed.
+	Unpad(data []byte) ([]byte, error)
+	// BlockSize returns the block size in bytes
+	BlockSize() int
+}
+
+// checkBlockSize panics with ErrInvalidBlockSize if the block size can't be
+// used with the padding schemes in this package
+func checkBlockSize(blockSize int) {
+	if blockSize < 1 || blockSize > 255 {
+		panic(ErrInvalidBlockSize)
+	}
+}
+
+// checkLength returns ErrInvalidLength if the data isn't a positive multiple of
+// the block size
+func checkLength(data []byte, blockSize int) error {
+	if len(data) == 0 || len(data)%blockSize != 0 {
+		return ErrInvalidLength
+	}
+	return nil
+}
+
+// pad returns a copy of data with room for padLen additional bytes
+func pad(data []byte, padLen int) []byte {
+	out := make([]byte, len(data)+padLen)
+	copy(out, data)
+	return out
+}
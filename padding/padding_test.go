@@ -0,0 +1,68 @@
+package padding_test
+
+import (
+	"testing"
+
+	"git.omicron.one/playground/cryptography/padding"
+	"github.com/stretchr/testify/assert"
+)
+
+// testRoundTrip pads messages of every length up to three blocks and checks
+// that unpadding restores them
+func testRoundTrip(t *testing.T, p padding.Padding) {
+	t.Helper()
+
+	bs := p.BlockSize()
+	for n := range 3*bs + 1 {
+		data := make([]byte, n)
+		for i := range data {
+			data[i] = byte(i + 1)
+		}
+		padded := p.Pad(data)
+		assert.Zero(t, len(padded)%bs)
+		assert.GreaterOrEqual(t, len(padded), n)
+		assert.Equal(t, data, padded[:n])
+
+		unpadded, err := p.Unpad(padded)
+		assert.Nil(t, err)
+		assert.Equal(t, data, unpadded)
+	}
+}
+
+// testInvalidLength checks the error for inputs that can't be padded data
+func testInvalidLength(t *testing.T, p padding.Padding) {
+	t.Helper()
+
+	bs := p.BlockSize()
+	for _, n := range []int{0, 1, bs - 1, bs + 1, 2*bs - 1} {
+		if n%bs == 0 && n != 0 {
+			continue
+		}
+		data, err := p.Unpad(make([]byte, n))
+		assert.ErrorIs(t, err, padding.ErrInvalidLength)
+		assert.Nil(t, data)
+	}
+}
+
+func TestInvalidBlockSize(t *testing.T) {
+	constructors := []func(int) padding.Padding{
+		padding.NewPKCS7,
+		padding.NewANSIX923,
+		padding.NewISO7816,
+		padding.NewZero,
+	}
+	for _, constructor := range constructors {
+		assert.PanicsWithValue(t, padding.ErrInvalidBlockSize, func() {
+			constructor(0)
+		})
+		assert.PanicsWithValue(t, padding.ErrInvalidBlockSize, func() {
+			constructor(-1)
+		})
+		assert.PanicsWithValue(t, padding.ErrInvalidBlockSize, func() {
+			constructor(256)
+		})
+		assert.NotPanics(t, func() {
+			assert.Equal(t, 255, constructor(255).BlockSize())
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package padding
+
+import "crypto/subtle"
+
+type pkcs7 struct {
+	blockSize int
+}
+
+// NewPKCS7 returns the padding scheme from PKCS #7 (RFC 5652) for the given
+// block size. Every padding byte is set to the number of padding bytes and at
+// least one byte of padding is always added.
+//
+// Panics with ErrInvalidBlockSize if blockSize < 1 or blockSize > 255.
+func NewPKCS7(blockSize int) Padding {
+	checkBlockSize(blockSize)
+	return &pkcs7{blockSize: blockSize}
+}
+
+func (p *pkcs7) BlockSize() int {
+	return p.blockSize
+}
+
+func (p *pkcs7) Pad(data []byte) []byte {
+	padLen := p.blockSize - len(data)%p.blockSize
+	out := pad(data, padLen)
+	for i := len(data); i < len(out); i++ {
+		out[i] = byte(padLen)
+	}
+	return out
+}
+
+func (p *pkcs7) Unpad(data []byte) ([]byte, error) {
+	if err := checkLength(data, p.blockSize); err != nil {
+		return nil, err
+	}
+
+	n := len(data)
+	padLen := int(data[n-1])
+	good := subtle.ConstantTimeLessOrEq(1, padLen) &
+		subtle.ConstantTimeLessOrEq(padLen, p.blockSize)
+
+	// Always inspect the whole last block
+	for i := 1; i <= p.blockSize; i++ {
+		inPadding := subtle.ConstantTimeLessOrEq(i, padLen)
+		matches := subtle.ConstantTimeByteEq(data[n-i], byte(padLen))
+		good &= ^inPadding&1 | matches
+	}
+
+	if good != 1 {
+		return nil, ErrInvalidPadding
+	}
+	return data[:n-padLen], nil
+}
@@ -0,0 +1,39 @@
+package padding_test
+
+import (
+	"testing"
+
+	"git.omicron.one/playground/cryptography/padding"
+	. "git.omicron.one/playground/cryptography/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPKCS7(t *testing.T) {
+	p := padding.NewPKCS7(8)
+	assert.Equal(t, 8, p.BlockSize())
+
+	assert.Equal(t, DeHex("0808080808080808"), p.Pad(nil))
+	assert.Equal(t, DeHex("aabbcc0505050505"), p.Pad(DeHex("aabbcc")))
+	assert.Equal(t, DeHex("aabbccddeeff0001"+"0808080808080808"), p.Pad(DeHex("aabbccddeeff0001")))
+
+	for _, bs := range []int{1, 8, 16, 255} {
+		testRoundTrip(t, padding.NewPKCS7(bs))
+		testInvalidLength(t, padding.NewPKCS7(bs))
+	}
+}
+
+func TestPKCS7Invalid(t *testing.T) {
+	p := padding.NewPKCS7(8)
+	invalid := []string{
+		"aabbccddeeff0000", // zero padding length
+		"aabbccddeeff0009", // padding longer than a block
+		"aabbccddee040303", // mismatching padding byte
+		"0708080808080808", // mismatch at the start of the block
+		"aabbccddeeffaaff",
+	}
+	for _, data := range invalid {
+		unpadded, err := p.Unpad(DeHex(data))
+		assert.ErrorIs(t, err, padding.ErrInvalidPadding, data)
+		assert.Nil(t, unpadded)
+	}
+}
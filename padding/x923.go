@@ -0,0 +1,51 @@
+package padding
+
+import "crypto/subtle"
+
+type ansiX923 struct {
+	blockSize int
+}
+
+// NewANSIX923 returns the ANSI X9.23 padding scheme for the given block size.
+// The padding consists of zero bytes followed by a final byte holding the
+// number of padding bytes. At least one byte of padding is always added.
+//
+// Panics with ErrInvalidBlockSize if blockSize < 1 or blockSize > 255.
+func NewANSIX923(blockSize int) Padding {
+	checkBlockSize(blockSize)
+	return &ansiX923{blockSize: blockSize}
+}
+
+func (p *ansiX923) BlockSize() int {
+	return p.blockSize
+}
+
+func (p *ansiX923) Pad(data []byte) []byte {
+	padLen := p.blockSize - len(data)%p.blockSize
+	out := pad(data, padLen)
+	out[len(out)-1] = byte(padLen)
+	return out
+}
+
+func (p *ansiX923) Unpad(data []byte) ([]byte, error) {
+	if err := checkLength(data, p.blockSize); err != nil {
+		return nil, err
+	}
+
+	n := len(data)
+	padLen := int(data[n-1])
+	good := subtle.ConstantTimeLessOrEq(1, padLen) &
+		subtle.ConstantTimeLessOrEq(padLen, p.blockSize)
+
+	// Always inspect the whole last block
+	for i := 2; i <= p.blockSize; i++ {
+		inPadding := subtle.ConstantTimeLessOrEq(i, padLen)
+		isZero := subtle.ConstantTimeByteEq(data[n-i], 0)
+		good &= ^inPadding&1 | isZero
+	}
+
+	if good != 1 {
+		return nil, ErrInvalidPadding
+	}
+	return data[:n-padLen], nil
+}
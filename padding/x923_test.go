@@ -0,0 +1,38 @@
+package padding_test
+
+import (
+	"testing"
+
+	"git.omicron.one/playground/cryptography/padding"
+	. "git.omicron.one/playground/cryptography/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestANSIX923(t *testing.T) {
+	p := padding.NewANSIX923(8)
+	assert.Equal(t, 8, p.BlockSize())
+
+	assert.Equal(t, DeHex("0000000000000008"), p.Pad(nil))
+	assert.Equal(t, DeHex("aabbcc0000000005"), p.Pad(DeHex("aabbcc")))
+	assert.Equal(t, DeHex("aabbccddeeff0001"+"0000000000000008"), p.Pad(DeHex("aabbccddeeff0001")))
+
+	for _, bs := range []int{1, 8, 16, 255} {
+		testRoundTrip(t, padding.NewANSIX923(bs))
+		testInvalidLength(t, padding.NewANSIX923(bs))
+	}
+}
+
+func TestANSIX923Invalid(t *testing.T) {
+	p := padding.NewANSIX923(8)
+	invalid := []string{
+		"aabbccddeeff0000", // zero padding length
+		"aabbccddeeff0009", // padding longer than a block
+		"aabbccdd00010003", // non-zero padding byte
+		"0100000000000008", // non-zero at the start of the block
+	}
+	for _, data := range invalid {
+		unpadded, err := p.Unpad(DeHex(data))
+		assert.ErrorIs(t, err, padding.ErrInvalidPadding, data)
+		assert.Nil(t, unpadded)
+	}
+}
@@ -0,0 +1,51 @@
+package padding
+
+import "crypto/subtle"
+
+type zero struct {
+	blockSize int
+}
+
+// NewZero returns the zero padding scheme for the given block size. The data is
+// extended with the minimal number of zero bytes, so no padding is added to
+// data that already is a non-empty multiple of the block size. Empty data is
+// padded to a single block of zeros, so the result can always be unpadded.
+// Because trailing zero bytes of the data can't be distinguished from the
+// padding, this scheme should only be used for data that never ends in a zero
+// byte. Unpad never returns ErrInvalidPadding.
+//
+// Panics with ErrInvalidBlockSize if blockSize < 1 or blockSize > 255.
+func NewZero(blockSize int) Padding {
+	checkBlockSize(blockSize)
+	return &zero{blockSize: blockSize}
+}
+
+func (p *zero) BlockSize() int {
+	return p.blockSize
+}
+
+func (p *zero) Pad(data []byte) []byte {
+	padLen := (p.blockSize - len(data)%p.blockSize) % p.blockSize
+	if len(data) == 0 {
+		padLen = p.blockSize
+	}
+	return pad(data, padLen)
+}
+
+func (p *zero) Unpad(data []byte) ([]byte, error) {
+	if err := checkLength(data, p.blockSize); err != nil {
+		return nil, err
+	}
+
+	n := len(data)
+	padLen := 0
+	trailing := 1
+
+	// Count the trailing zeros of the last block, always inspecting all of it
+	for i := 1; i <= p.blockSize; i++ {
+		trailing &= subtle.ConstantTimeByteEq(data[n-i], 0)
+		padLen += trailing
+	}
+
+	return data[:n-padLen], nil
+}
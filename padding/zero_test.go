@@ -0,0 +1,36 @@
+package padding_test
+
+import (
+	"testing"
+
+	"git.omicron.one/playground/cryptography/padding"
+	. "git.omicron.one/playground/cryptography/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZero(t *testing.T) {
+	p := padding.NewZero(8)
+	assert.Equal(t, 8, p.BlockSize())
+
+	assert.Equal(t, make([]byte, 8), p.Pad(nil))
+	assert.Equal(t, DeHex("aabbcc0000000000"), p.Pad(DeHex("aabbcc")))
+	assert.Equal(t, DeHex("aabbccddeeff0001"), p.Pad(DeHex("aabbccddeeff0001")))
+
+	unpadded, err := p.Unpad(DeHex("aabbcc0000000000"))
+	assert.Nil(t, err)
+	assert.Equal(t, DeHex("aabbcc"), unpadded)
+
+	// Trailing zeros in the data are removed as well
+	unpadded, err = p.Unpad(DeHex("aabbccddeeff0000"))
+	assert.Nil(t, err)
+	assert.Equal(t, DeHex("aabbccddeeff"), unpadded)
+
+	// Only the last block is inspected
+	unpadded, err = p.Unpad(DeHex("aa00000000000000" + "0000000000000000"))
+	assert.Nil(t, err)
+	assert.Equal(t, DeHex("aa00000000000000"), unpadded)
+
+	for _, bs := range []int{1, 8, 16, 255} {
+		testInvalidLength(t, padding.NewZero(bs))
+	}
+}
@@ -0,0 +1,52 @@
+package randomness
+
+import "math"
+
+// CumulativeSums performs the cumulative sums test of SP 800-22 section 2.13,
+// which checks the maximal excursion of the random walk defined by the
+// sequence. It returns the p-values of the forward and the backward walk. At
+// least 100 bits are recommended.
+// Returns ErrInsufficientData if s is empty.
+func CumulativeSums(s Sequence) (float64, float64, error) {
+	if len(s) == 0 {
+		return 0, 0, ErrInsufficientData
+	}
+
+	// The maximal excursion of the backward walk is the maximal distance
+	// between the total and any partial sum of the forward walk
+	sum, lo, hi := 0, 0, 0
+	for _, b := range s {
+		sum += 2*int(b) - 1
+		lo = min(lo, sum)
+		hi = max(hi, sum)
+	}
+	forward := max(hi, -lo)
+	backward := max(sum-lo, hi-sum)
+	return cusumPValue(len(s), forward), cusumPValue(len(s), backward), nil
+}
+
+// cusumPValue returns the p-value for a maximal excursion z of a walk with n
+// steps
+func cusumPValue(n, z int) float64 {
+	// z is at least one for a non-empty sequence
+	sqrtN := math.Sqrt(float64(n))
+	nz := float64(n) / float64(z)
+	phi := func(k, d int) float64 {
+		return normal(float64(4*k+d) * float64(z) / sqrtN)
+	}
+
+	// The bounds truncate towards zero like the reference implementation
+	p := 1.0
+	for k := int((-nz + 1) / 4); k <= int((nz-1)/4); k++ {
+		p -= phi(k, 1) - phi(k, -1)
+	}
+	for k := int((-nz - 3) / 4); k <= int((nz-1)/4); k++ {
+		p += phi(k, 3) - phi(k, 1)
+	}
+	return p
+}
+
+// normal returns the standard normal cumulative distribution function
+func normal(x float64) float64 {
+	return math.Erfc(-x/math.Sqrt2) / 2
+}
@@ -0,0 +1,22 @@
+package randomness_test
+
+import (
+	"testing"
+
+	"git.omicron.one/playground/cryptography/randomness"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCumulativeSums(t *testing.T) {
+	forward, _, err := randomness.CumulativeSums(sequence("1011010111"))
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.4116588, forward, 1e-6)
+
+	forward, backward, err := randomness.CumulativeSums(sequence(example100))
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.219194, forward, 1e-6)
+	assert.InDelta(t, 0.114866, backward, 1e-6)
+
+	_, _, err = randomness.CumulativeSums(nil)
+	assert.ErrorIs(t, err, randomness.ErrInsufficientData)
+}
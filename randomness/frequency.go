@@ -0,0 +1,53 @@
+package randomness
+
+import (
+	"math"
+
+	"git.omicron.one/playground/cryptography/internal/special"
+)
+
+// Frequency performs the frequency (monobit) test of SP 800-22 section 2.1,
+// which checks that ones and zeros are about equally common. At least 100 bits
+// are recommended.
+// Returns ErrInsufficientData if s is empty.
+func Frequency(s Sequence) (float64, error) {
+	n := len(s)
+	if n == 0 {
+		return 0, ErrInsufficientData
+	}
+
+	sum := 0
+	for _, b := range s {
+		sum += 2*int(b) - 1
+	}
+	obs := math.Abs(float64(sum)) / math.Sqrt(float64(n))
+	return math.Erfc(obs / math.Sqrt2), nil
+}
+
+// BlockFrequency performs the frequency test within a block of SP 800-22
+// section 2.2, which checks the proportion of ones in non-overlapping blocks
+// of m bits. Trailing bits that don't fill a block are ignored. It is
+// recommended that m >= 20, m > 0.01n and that there are less than 100 blocks.
+// Returns ErrInvalidParameter if m < 1 or ErrInsufficientData if s is shorter
+// than m.
+func BlockFrequency(s Sequence, m int) (float64, error) {
+	if m < 1 {
+		return 0, ErrInvalidParameter
+	}
+	blocks := len(s) / m
+	if blocks == 0 {
+		return 0, ErrInsufficientData
+	}
+
+	chi2 := 0.0
+	for i := range blocks {
+		ones := 0
+		for _, b := range s[i*m : (i+1)*m] {
+			ones += int(b)
+		}
+		d := float64(ones)/float64(m) - 0.5
+		chi2 += d * d
+	}
+	chi2 *= 4 * float64(m)
+	return special.Igamc(float64(blocks)/2, chi2/2), nil
+}
@@ -0,0 +1,36 @@
+package randomness_test
+
+import (
+	"testing"
+
+	"git.omicron.one/playground/cryptography/randomness"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFrequency(t *testing.T) {
+	p, err := randomness.Frequency(sequence("1011010101"))
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.527089, p, 1e-6)
+
+	p, err = randomness.Frequency(sequence(example100))
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.109599, p, 1e-6)
+
+	_, err = randomness.Frequency(nil)
+	assert.ErrorIs(t, err, randomness.ErrInsufficientData)
+}
+
+func TestBlockFrequency(t *testing.T) {
+	p, err := randomness.BlockFrequency(sequence("0110011010"), 3)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.801252, p, 1e-6)
+
+	p, err = randomness.BlockFrequency(sequence(example100), 10)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.706438, p, 1e-6)
+
+	_, err = randomness.BlockFrequency(sequence("0110"), 0)
+	assert.ErrorIs(t, err, randomness.ErrInvalidParameter)
+	_, err = randomness.BlockFrequency(sequence("0110"), 5)
+	assert.ErrorIs(t, err, randomness.ErrInsufficientData)
+}
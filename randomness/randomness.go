@@ -0,0 +1,118 @@
+// Package randomness implements a subset of the statistical tests for random
+// number generators from NIST SP 800-22 rev. 1a,
+// https://csrc.nist.gov/pubs/sp/800/22/r1/upd1/final.
+//
+// Every test returns one or more p-values. A sequence is considered random by
+// a test if all its p-values are at least the chosen significance level,
+// usually 0.01. The parameter recommendations of SP 800-22 are not enforced,
+// but with too short sequences the p-values are meaningless.
+package randomness
+
+import (
+	"errors"
+	"math/bits"
+)
+
+var (
+	ErrInsufficientData = errors.New("Sequence is too short for the test")
+	ErrInvalidParameter = errors.New("Invalid test parameter")
+)
+
+// Sequence is a sequence of bits with one bit per element, each 0 or 1
+type Sequence []byte
+
+// NewSequence converts bytes to a Sequence, starting with the most
+// significant bit of the first byte
+func NewSequence(data []byte) Sequence {
+	s := make(Sequence, 8*len(data))
+	for i, b := range data {
+		for j := range 8 {
+			s[8*i+j] = b >> (7 - j) & 1
+		}
+	}
+	return s
+}
+
+// Result holds the p-values of a single test
+type Result struct {
+	Name    string    `json:"name"`
+	PValues []float64 `json:"p_values"`
+}
+
+// Passed returns true if none of the p-values is below the significance
+// level alpha
+func (r Result) Passed(alpha float64) bool {
+	for _, p := range r.PValues {
+		if p < alpha {
+			return false
+		}
+	}
+	return true
+}
+
+// MinSuiteLength is the minimum number of bits accepted by Suite
+const MinSuiteLength = 128
+
+// Suite runs all tests of the package on s, with parameters chosen from the
+// length of the sequence. The results are meaningful from about 10^6 bits on,
+// the length used in SP 800-22.
+// Returns ErrInsufficientData if s is shorter than MinSuiteLength.
+func Suite(s Sequence) ([]Result, error) {
+	n := len(s)
+	if n < MinSuiteLength {
+		return nil, ErrInsufficientData
+	}
+
+	// Follow the recommendations M >= 20, M > 0.01n and N < 100 for the block
+	// frequency test, m < log2(n) - 2 for the serial test and m < log2(n) - 5
+	// for the approximate entropy test
+	log2n := bits.Len(uint(n)) - 1
+	blockSize := max(20, n/100+1)
+	serialLength := min(16, log2n-3)
+	entropyLength := min(10, log2n-6)
+
+	var results []Result
+	add := func(name string, p ...float64) {
+		results = append(results, Result{Name: name, PValues: p})
+	}
+
+	p, err := Frequency(s)
+	if err != nil {
+		return nil, err
+	}
+	add("Frequency", p)
+
+	if p, err = BlockFrequency(s, blockSize); err != nil {
+		return nil, err
+	}
+	add("BlockFrequency", p)
+
+	if p, err = Runs(s); err != nil {
+		return nil, err
+	}
+	add("Runs", p)
+
+	if p, err = LongestRun(s); err != nil {
+		return nil, err
+	}
+	add("LongestRun", p)
+
+	p1, p2, err := Serial(s, serialLength)
+	if err != nil {
+		return nil, err
+	}
+	add("Serial", p1, p2)
+
+	if p, err = ApproximateEntropy(s, entropyLength); err != nil {
+		return nil, err
+	}
+	add("ApproximateEntropy", p)
+
+	forward, backward, err := CumulativeSums(s)
+	if err != nil {
+		return nil, err
+	}
+	add("CumulativeSums", forward, backward)
+
+	return results, nil
+}
@@ -0,0 +1,88 @@
+package randomness_test
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"git.omicron.one/playground/cryptography/randomness"
+	"github.com/stretchr/testify/assert"
+)
+
+// The examples of SP 800-22, ignoring whitespace
+const (
+	example100 = "11001001000011111101101010100010001000010110100011" +
+		"00001000110100110001001100011001100010100010111000"
+	example128 = "11001100000101010110110001001100111000000000001001" +
+		"00110101010001000100111101011010000000110101111100" +
+		"1100111001101101100010110010"
+)
+
+// sequence parses a string of '0' and '1' characters
+func sequence(bits string) randomness.Sequence {
+	s := make(randomness.Sequence, 0, len(bits))
+	for _, c := range strings.ReplaceAll(bits, " ", "") {
+		s = append(s, byte(c-'0'))
+	}
+	return s
+}
+
+// repeat repeats pattern to n characters
+func repeat(pattern string, n int) string {
+	return strings.Repeat(pattern, n/len(pattern)+1)[:n]
+}
+
+// randomSequence returns n pseudorandom bits
+func randomSequence(n int, seed uint64) randomness.Sequence {
+	rng := rand.New(rand.NewPCG(seed, 0))
+	s := make(randomness.Sequence, n)
+	for i := range s {
+		s[i] = byte(rng.Uint32() & 1)
+	}
+	return s
+}
+
+func TestNewSequence(t *testing.T) {
+	assert.Equal(t, sequence("10000000 00001111 10100101"), randomness.NewSequence([]byte{0x80, 0x0f, 0xa5}))
+	assert.Empty(t, randomness.NewSequence(nil))
+}
+
+func TestResultPassed(t *testing.T) {
+	r := randomness.Result{Name: "Serial", PValues: []float64{0.5, 0.02}}
+	assert.True(t, r.Passed(0.01))
+	assert.False(t, r.Passed(0.05))
+}
+
+func TestSuite(t *testing.T) {
+	results, err := randomness.Suite(randomSequence(1<<16, 1))
+	assert.Nil(t, err)
+
+	names := []string{
+		"Frequency",
+		"BlockFrequency",
+		"Runs",
+		"LongestRun",
+		"Serial",
+		"ApproximateEntropy",
+		"CumulativeSums",
+	}
+	assert.Len(t, results, len(names))
+	for i, r := range results {
+		assert.Equal(t, names[i], r.Name)
+		assert.True(t, r.Passed(0.001), "%s: %v", r.Name, r.PValues)
+	}
+
+	// A biased sequence must fail
+	s := randomSequence(1<<16, 2)
+	for i := 0; i < len(s); i += 8 {
+		s[i] = 1
+	}
+	results, err = randomness.Suite(s)
+	assert.Nil(t, err)
+	assert.False(t, results[0].Passed(0.01))
+
+	_, err = randomness.Suite(sequence(example100))
+	assert.ErrorIs(t, err, randomness.ErrInsufficientData)
+	_, err = randomness.Suite(sequence(example128))
+	assert.Nil(t, err)
+}
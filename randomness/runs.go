@@ -0,0 +1,100 @@
+package randomness
+
+import (
+	"math"
+
+	"git.omicron.one/playground/cryptography/internal/special"
+)
+
+// Runs performs the runs test of SP 800-22 section 2.3, which checks that the
+// number of runs of identical bits is as expected. At least 100 bits are
+// recommended.
+// Returns ErrInsufficientData if s is empty.
+func Runs(s Sequence) (float64, error) {
+	n := float64(len(s))
+	if len(s) == 0 {
+		return 0, ErrInsufficientData
+	}
+
+	ones := 0
+	for _, b := range s {
+		ones += int(b)
+	}
+	pi := float64(ones) / n
+
+	// The test is not applicable if the frequency test fails
+	if math.Abs(pi-0.5) >= 2/math.Sqrt(n) {
+		return 0, nil
+	}
+
+	runs := 1
+	for i := 1; i < len(s); i++ {
+		if s[i] != s[i-1] {
+			runs++
+		}
+	}
+	v := pi * (1 - pi)
+	return math.Erfc(math.Abs(float64(runs)-2*n*v) / (2 * math.Sqrt(2*n) * v)), nil
+}
+
+// longestRunClass holds the parameters of the longest run test for one block
+// size: the longest runs counted in the first and last class and the
+// probabilities of each class
+type longestRunClass struct {
+	blockSize int
+	min, max  int
+	pi        []float64
+}
+
+// longestRunClasses holds the parameters of SP 800-22 section 3.4 for
+// sequences of at least 128, 6272 and 750000 bits. Like the reference
+// implementation it uses more precise probabilities than the rounded ones
+// listed in the document.
+var longestRunClasses = []longestRunClass{
+	{8, 1, 4, []float64{0.21484375, 0.3671875, 0.23046875, 0.1875}},
+	{128, 4, 9, []float64{0.1174035788, 0.242955959, 0.249363483, 0.17517706, 0.102701071, 0.112398847}},
+	{10000, 10, 16, []float64{0.0882, 0.2092, 0.2483, 0.1933, 0.1208, 0.0675, 0.0727}},
+}
+
+// LongestRun performs the test for the longest run of ones in a block of
+// SP 800-22 section 2.4. The block size is 8, 128 or 10000 bits depending on
+// the length of the sequence.
+// Returns ErrInsufficientData if s is shorter than 128 bits.
+func LongestRun(s Sequence) (float64, error) {
+	var class longestRunClass
+	switch n := len(s); {
+	case n < 128:
+		return 0, ErrInsufficientData
+	case n < 6272:
+		class = longestRunClasses[0]
+	case n < 750000:
+		class = longestRunClasses[1]
+	default:
+		class = longestRunClasses[2]
+	}
+
+	m := class.blockSize
+	blocks := len(s) / m
+	counts := make([]int, len(class.pi))
+	for i := range blocks {
+		longest, run := 0, 0
+		for _, b := range s[i*m : (i+1)*m] {
+			if b == 1 {
+				run++
+				longest = max(longest, run)
+			} else {
+				run = 0
+			}
+		}
+		longest = min(max(longest, class.min), class.max)
+		counts[longest-class.min]++
+	}
+
+	chi2 := 0.0
+	for i, pi := range class.pi {
+		expected := float64(blocks) * pi
+		d := float64(counts[i]) - expected
+		chi2 += d * d / expected
+	}
+	return special.Igamc(float64(len(class.pi)-1)/2, chi2/2), nil
+}
@@ -0,0 +1,46 @@
+package randomness_test
+
+import (
+	"testing"
+
+	"git.omicron.one/playground/cryptography/randomness"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuns(t *testing.T) {
+	p, err := randomness.Runs(sequence("1001101011"))
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.147232, p, 1e-6)
+
+	p, err = randomness.Runs(sequence(example100))
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.500798, p, 1e-6)
+
+	// Too many ones
+	p, err = randomness.Runs(sequence(repeat("11110", 100)))
+	assert.Nil(t, err)
+	assert.Zero(t, p)
+
+	_, err = randomness.Runs(nil)
+	assert.ErrorIs(t, err, randomness.ErrInsufficientData)
+}
+
+func TestLongestRun(t *testing.T) {
+	p, err := randomness.LongestRun(sequence(example128))
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.180609, p, 1e-6)
+
+	// The larger block sizes
+	for _, n := range []int{6272, 750000} {
+		p, err = randomness.LongestRun(randomSequence(n, 3))
+		assert.Nil(t, err)
+		assert.Greater(t, p, 0.001)
+
+		p, err = randomness.LongestRun(make(randomness.Sequence, n))
+		assert.Nil(t, err)
+		assert.Less(t, p, 1e-6)
+	}
+
+	_, err = randomness.LongestRun(sequence(example100))
+	assert.ErrorIs(t, err, randomness.ErrInsufficientData)
+}
@@ -0,0 +1,96 @@
+package randomness
+
+import (
+	"math"
+
+	"git.omicron.one/playground/cryptography/internal/special"
+)
+
+// maxPatternLength limits the pattern length of the serial and approximate
+// entropy tests, which count all 2^m patterns
+const maxPatternLength = 24
+
+// patternCounts returns the number of occurrences of every m-bit pattern in s,
+// including the patterns that wrap around from the end to the start
+func patternCounts(s Sequence, m int) []int {
+	counts := make([]int, 1<<m)
+	if m == 0 {
+		counts[0] = len(s)
+		return counts
+	}
+
+	mask := 1<<m - 1
+	pattern := 0
+	for i := range m - 1 {
+		pattern = pattern<<1 | int(s[i])
+	}
+	for i := range s {
+		pattern = (pattern<<1 | int(s[(i+m-1)%len(s)])) & mask
+		counts[pattern]++
+	}
+	return counts
+}
+
+// psi2 returns the statistic psi^2_m of the serial test
+func psi2(s Sequence, m int) float64 {
+	if m <= 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, c := range patternCounts(s, m) {
+		sum += float64(c) * float64(c)
+	}
+	n := float64(len(s))
+	return sum*float64(uint(1)<<m)/n - n
+}
+
+// Serial performs the serial test of SP 800-22 section 2.11, which checks the
+// frequency of all overlapping m-bit patterns. It returns two p-values. It is
+// recommended that m < log2(n) - 2.
+// Returns ErrInvalidParameter if m < 2 or m is larger than 24, or
+// ErrInsufficientData if s is shorter than m bits.
+func Serial(s Sequence, m int) (float64, float64, error) {
+	if m < 2 || m > maxPatternLength {
+		return 0, 0, ErrInvalidParameter
+	}
+	if len(s) < m {
+		return 0, 0, ErrInsufficientData
+	}
+
+	p0, p1, p2 := psi2(s, m), psi2(s, m-1), psi2(s, m-2)
+	delta1 := p0 - p1
+	delta2 := p0 - 2*p1 + p2
+	return special.Igamc(math.Ldexp(1, m-2), delta1/2), special.Igamc(math.Ldexp(1, m-3), delta2/2), nil
+}
+
+// phi returns the statistic phi^(m) of the approximate entropy test
+func phi(s Sequence, m int) float64 {
+	n := float64(len(s))
+	sum := 0.0
+	for _, c := range patternCounts(s, m) {
+		if c > 0 {
+			p := float64(c) / n
+			sum += p * math.Log(p)
+		}
+	}
+	return sum
+}
+
+// ApproximateEntropy performs the approximate entropy test of SP 800-22
+// section 2.12, which compares the frequency of overlapping patterns of m and
+// m+1 bits. It is recommended that m < log2(n) - 5.
+// Returns ErrInvalidParameter if m < 1 or m is larger than 23, or
+// ErrInsufficientData if s is shorter than m+1 bits.
+func ApproximateEntropy(s Sequence, m int) (float64, error) {
+	if m < 1 || m+1 > maxPatternLength {
+		return 0, ErrInvalidParameter
+	}
+	if len(s) < m+1 {
+		return 0, ErrInsufficientData
+	}
+
+	n := float64(len(s))
+	apen := phi(s, m) - phi(s, m+1)
+	chi2 := 2 * n * (math.Ln2 - apen)
+	return special.Igamc(math.Ldexp(1, m-1), chi2/2), nil
+}
@@ -0,0 +1,49 @@
+package randomness_test
+
+import (
+	"testing"
+
+	"git.omicron.one/playground/cryptography/randomness"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSerial(t *testing.T) {
+	p1, p2, err := randomness.Serial(sequence("0011011101"), 3)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.808792, p1, 1e-6)
+	assert.InDelta(t, 0.670320, p2, 1e-6)
+
+	p1, p2, err = randomness.Serial(randomSequence(1<<16, 4), 12)
+	assert.Nil(t, err)
+	assert.Greater(t, p1, 0.001)
+	assert.Greater(t, p2, 0.001)
+
+	_, _, err = randomness.Serial(sequence("0011011101"), 1)
+	assert.ErrorIs(t, err, randomness.ErrInvalidParameter)
+	_, _, err = randomness.Serial(sequence("0011011101"), 25)
+	assert.ErrorIs(t, err, randomness.ErrInvalidParameter)
+	_, _, err = randomness.Serial(sequence("01"), 3)
+	assert.ErrorIs(t, err, randomness.ErrInsufficientData)
+}
+
+func TestApproximateEntropy(t *testing.T) {
+	p, err := randomness.ApproximateEntropy(sequence("0100110101"), 3)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.261961, p, 1e-6)
+
+	p, err = randomness.ApproximateEntropy(sequence(example100), 2)
+	assert.Nil(t, err)
+	assert.InDelta(t, 0.235301, p, 1e-6)
+
+	// A periodic sequence has a very low entropy
+	p, err = randomness.ApproximateEntropy(sequence(repeat("0110", 100)), 2)
+	assert.Nil(t, err)
+	assert.Less(t, p, 1e-6)
+
+	_, err = randomness.ApproximateEntropy(sequence("0100110101"), 0)
+	assert.ErrorIs(t, err, randomness.ErrInvalidParameter)
+	_, err = randomness.ApproximateEntropy(sequence("0100110101"), 24)
+	assert.ErrorIs(t, err, randomness.ErrInvalidParameter)
+	_, err = randomness.ApproximateEntropy(sequence("01"), 2)
+	assert.ErrorIs(t, err, randomness.ErrInsufficientData)
+}